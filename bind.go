@@ -0,0 +1,251 @@
+package xflag
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/goaltools/xflag/cflag/types"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+// Bind registers a flag for every exported field of the struct
+// the v pointer refers to. Values of the flags are stored
+// in the fields. The following tags are supported:
+//	xflag    - name of the flag (a lower case field name is used if omitted,
+//	           "-" means the field must be ignored);
+//	default  - default value of the flag (elements of slices are separated
+//	           by commas, the current value of the field is used if omitted);
+//	usage    - usage string of the flag;
+//	secret   - "true" if the flag must be marked as secret (see Secret),
+//	           "file" if its value may also be read from a file (see SecretFile);
+//	required - "true" if the flag must be marked as required (see Require).
+// Fields of nested structs are registered as "section:key" flags
+// using the Separator, the name of the struct field is used
+// as a section. Embedded structs without names are flattened.
+// Slice fields are registered using cflag/types and the ArrLiteral.
+// Fields of the slice and map types of cflag/types are registered
+// with the ArrLiteral and MapLiteral respectively.
+// Example:
+//	type Config struct {
+//		DB struct {
+//			Port  int      `xflag:"port" default:"5432" usage:"port of the database"`
+//			Hosts []string `xflag:"hosts" default:"localhost"`
+//		} `xflag:"database"`
+//	}
+// The struct above is represented by "database:port" and "database:hosts[]" flags.
+func (c *Context) Bind(fset *flag.FlagSet, v interface{}) error {
+	p := reflect.ValueOf(v)
+	if p.Kind() != reflect.Ptr || p.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf(`pointer to a struct expected, got "%T"`, v)
+	}
+	return c.bind(fset, p.Elem(), "")
+}
+
+// ParseStruct is a shorthand for the following code:
+//	c := xflag.New(INIConfigParser, os.Args[1:])
+//	err := c.Bind(flag.CommandLine, v)
+//	if err != nil {
+//		...
+//	}
+//	err = c.Files(files...)
+//	if err != nil {
+//		...
+//	}
+//	err = c.Parse()
+//	if err != nil {
+//		...
+//	}
+func ParseStruct(v interface{}, files ...string) error {
+	c := New(ini.New(nil), os.Args[1:])
+	if err := c.Bind(flag.CommandLine, v); err != nil {
+		return err
+	}
+	if err := c.Files(files...); err != nil {
+		return err
+	}
+	return c.Parse()
+}
+
+// bind registers flags for the fields of the struct s.
+// Their names are prefixed with the prefix.
+func (c *Context) bind(fset *flag.FlagSet, s reflect.Value, prefix string) error {
+	t := s.Type()
+	for i := 0; i < t.NumField(); i++ {
+		// Ignore unexported fields (except embedded structs) and
+		// the ones that were explicitly marked as ignored.
+		sf := t.Field(i)
+		name, tagged := sf.Tag.Lookup("xflag")
+		value := reflect.PtrTo(sf.Type).Implements(valueType)
+		nested := sf.Type.Kind() == reflect.Struct && !value
+		if sf.PkgPath != "" && !(sf.Anonymous && nested) || name == "-" {
+			continue
+		}
+		if !tagged {
+			name = strings.ToLower(sf.Name)
+		}
+		name = prefix + strings.TrimSuffix(strings.TrimSuffix(name, c.ArrLiteral), c.MapLiteral)
+
+		// Nested structs are registered recursively unless
+		// they implement flag.Value themselves.
+		p := s.Field(i).Addr()
+		if nested {
+			switch {
+			case sf.Anonymous && !tagged:
+				name = prefix
+			default:
+				name += c.Separator
+			}
+			if err := c.bind(fset, p.Elem(), name); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := c.bindField(fset, p, sf, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindField registers a flag with the name for the field p
+// points to and assigns the default value from the tag, if any.
+func (c *Context) bindField(fset *flag.FlagSet, p reflect.Value, sf reflect.StructField, name string) error {
+	usage := sf.Tag.Get("usage")
+	arr := false
+	switch v := p.Interface().(type) {
+	case flag.Value:
+		if _, ok := types.Elements(v); ok {
+			name += c.literal(v)
+			arr = true
+		}
+		fset.Var(v, name, usage)
+	case *string:
+		fset.StringVar(v, name, *v, usage)
+	case *bool:
+		fset.BoolVar(v, name, *v, usage)
+	case *int:
+		fset.IntVar(v, name, *v, usage)
+	case *int64:
+		fset.Int64Var(v, name, *v, usage)
+	case *uint:
+		fset.UintVar(v, name, *v, usage)
+	case *uint64:
+		fset.Uint64Var(v, name, *v, usage)
+	case *float64:
+		fset.Float64Var(v, name, *v, usage)
+	case *time.Duration:
+		fset.DurationVar(v, name, *v, usage)
+	default:
+		sv, ok := sliceValue(v)
+		if !ok {
+			return fmt.Errorf(`field "%s" is of unsupported type "%s"`, sf.Name, sf.Type)
+		}
+		name += c.ArrLiteral
+		fset.Var(sv, name, usage)
+		arr = true
+	}
+
+	switch sf.Tag.Get("secret") {
+	case "true":
+		c.Secret(name)
+	case "file":
+		c.SecretFile(name)
+	}
+	if sf.Tag.Get("required") == "true" {
+		c.Require(name)
+	}
+
+	// Assign the default value if it is specified.
+	def, ok := sf.Tag.Lookup("default")
+	if !ok {
+		return nil
+	}
+	f := fset.Lookup(name)
+	ss := []string{def}
+	if arr {
+		ss = strings.Split(def, ",")
+	}
+	for i := range ss {
+		if err := f.Value.Set(ss[i]); err != nil {
+			return fmt.Errorf(`invalid default value "%s" of field "%s": %v`, ss[i], sf.Name, err)
+		}
+	}
+	if arr {
+		f.Value.Set(types.EOI)
+	}
+	f.DefValue = f.Value.String()
+	return nil
+}
+
+// literal returns the MapLiteral for the map types of the cflag/types
+// and the ArrLiteral for their slice types.
+func (c *Context) literal(v flag.Value) string {
+	if f := reflect.Indirect(reflect.ValueOf(v)).FieldByName("Value"); f.Kind() == reflect.Map {
+		return c.MapLiteral
+	}
+	return c.ArrLiteral
+}
+
+// valueType is a type of the flag.Value interface.
+var valueType = reflect.TypeOf((*flag.Value)(nil)).Elem()
+
+// slice is a flag.Value that wraps one of the cflag/types
+// and copies its value to a struct field after every change.
+type slice struct {
+	flag.Value
+	sync func()
+}
+
+// String returns the wrapped value in a human readable format.
+func (s *slice) String() string {
+	if s.Value == nil {
+		return "[]"
+	}
+	return s.Value.String()
+}
+
+// Set calls the Set method of the wrapped value and
+// updates the struct field.
+func (s *slice) Set(v string) error {
+	err := s.Value.Set(v)
+	s.sync()
+	return err
+}
+
+// sliceValue returns a flag.Value for the pointer to a slice.
+// False is returned as a second argument if the type
+// of the slice is not supported.
+func sliceValue(v interface{}) (flag.Value, bool) {
+	switch p := v.(type) {
+	case *[]string:
+		t := &types.Strings{Value: *p}
+		return &slice{t, func() { *p = t.Value }}, true
+	case *[]bool:
+		t := &types.Bools{Value: *p}
+		return &slice{t, func() { *p = t.Value }}, true
+	case *[]int:
+		t := &types.Ints{Value: *p}
+		return &slice{t, func() { *p = t.Value }}, true
+	case *[]int64:
+		t := &types.Int64s{Value: *p}
+		return &slice{t, func() { *p = t.Value }}, true
+	case *[]uint:
+		t := &types.Uints{Value: *p}
+		return &slice{t, func() { *p = t.Value }}, true
+	case *[]uint64:
+		t := &types.Uint64s{Value: *p}
+		return &slice{t, func() { *p = t.Value }}, true
+	case *[]float64:
+		t := &types.Float64s{Value: *p}
+		return &slice{t, func() { *p = t.Value }}, true
+	case *[]time.Duration:
+		t := &types.Durations{Value: *p}
+		return &slice{t, func() { *p = t.Value }}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,115 @@
+package xflag
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/goaltools/xflag/cflag/types"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+type bindCommon struct {
+	Debug bool `usage:"enable debug mode"`
+}
+
+type bindConfig struct {
+	bindCommon
+
+	Name   string   `default:"James"`
+	Tags   []string `xflag:"tags[]" default:"a,b"`
+	Ports  []int    `xflag:"ports" default:"80,443"`
+	Rate   float64
+	Level  types.Strings   `xflag:"levels[]"`
+	Modes  types.Enums     `xflag:"modes"`
+	Labels types.StringMap `xflag:"labels"`
+	DB     struct {
+		Port    int           `xflag:"port" default:"5432" usage:"port of the database"`
+		Timeout time.Duration `xflag:"timeout"`
+		Extra   struct {
+			Size uint64 `default:"10"`
+		}
+	} `xflag:"database"`
+	Ignored string `xflag:"-"`
+	private string
+}
+
+func TestContext_Bind(t *testing.T) {
+	var conf bindConfig
+	conf.Rate = 0.5
+	conf.Modes.Choices = []string{"fast", "safe"}
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	c := New(ini.New(nil), []string{"--debug", "--ports[]", "8080"})
+	if err := c.Bind(fset, &conf); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+
+	var names []string
+	fset.VisitAll(func(f *flag.Flag) {
+		names = append(names, f.Name)
+	})
+	exp := []string{
+		"database:extra:size", "database:port", "database:timeout",
+		"debug", "labels{}", "levels[]", "modes[]", "name", "ports[]", "rate", "tags[]",
+	}
+	if !reflect.DeepEqual(names, exp) {
+		t.Errorf(`Expected "%v", got "%v".`, exp, names)
+	}
+	for name, exp := range map[string]string{
+		"database:port": "5432",
+		"ports[]":       "[80; 443]",
+		"rate":          "0.5",
+	} {
+		if res := fset.Lookup(name).DefValue; res != exp {
+			t.Errorf(`Expected "%s", got "%s".`, exp, res)
+		}
+	}
+	if res := fset.Lookup("database:port").Usage; res != "port of the database" {
+		t.Errorf(`Unexpected usage "%s".`, res)
+	}
+
+	if err := c.Files("./testdata/bind.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	for _, v := range []struct {
+		val, exp interface{}
+	}{
+		{conf.Debug, true},
+		{conf.Name, "Bob"},
+		{conf.Tags, []string{"x", "y"}},
+		{conf.Ports, []int{8080}},
+		{conf.Rate, 0.5},
+		{conf.DB.Port, 3306},
+		{conf.DB.Timeout, 5 * time.Second},
+		{conf.DB.Extra.Size, uint64(10)},
+		{conf.Level.Value, []string{"debug", "info"}},
+		{conf.Modes.Value, []string{"safe"}},
+		{conf.Labels.Value, map[string]string{"env": "prod"}},
+	} {
+		if !reflect.DeepEqual(v.val, v.exp) {
+			t.Errorf(`Incorrect value of the field. Expected "%v", got "%v".`, v.exp, v.val)
+		}
+	}
+}
+
+func TestContext_Bind_Errors(t *testing.T) {
+	for _, v := range []interface{}{
+		bindConfig{},
+		new(int),
+		&struct{ Ch chan int }{},
+		&struct {
+			Port int `default:"abc"`
+		}{},
+	} {
+		fset := flag.NewFlagSet("test", flag.ContinueOnError)
+		if err := New(ini.New(nil), nil).Bind(fset, v); err == nil {
+			t.Errorf(`Error expected for "%T".`, v)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package cflag
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/goaltools/xflag/cflag/types"
+)
+
+// Enum is an equivalent of flag.String but for values that must be one
+// of the choices. It defines an enum flag with the specified name,
+// default value, and usage string. The choices are appended to the
+// usage string. The returned value is the address of a string variable
+// that stores the value of the flag.
+func Enum(name, value string, choices []string, usage string) *string {
+	p := &types.Enum{Value: value, Choices: choices}
+	flag.Var(p, name, ChoicesUsage(usage, choices))
+	return &p.Value
+}
+
+// Enums is an equivalent of Strings but for values that must be one
+// of the choices. It defines a slice flag with the specified name,
+// default value, and usage string. The choices are appended to the
+// usage string. The returned value is the address of a string slice
+// variable that stores the value of the flag.
+func Enums(name string, value, choices []string, usage string) *[]string {
+	p := &types.Enums{Value: value, Choices: choices}
+	flag.Var(p, name, ChoicesUsage(usage, choices))
+	return &p.Value
+}
+
+// EnumFold is an equivalent of Enum but values are compared with the
+// choices case-insensitively, e.g. "DEBUG" is accepted as "debug".
+// The choice is stored rather than the value as is.
+func EnumFold(name, value string, choices []string, usage string) *string {
+	p := &types.Enum{Value: value, Choices: choices, Fold: true}
+	flag.Var(p, name, ChoicesUsage(usage, choices))
+	return &p.Value
+}
+
+// EnumsFold is an equivalent of Enums but values are compared with the
+// choices case-insensitively. The choices are stored rather than
+// the values as is.
+func EnumsFold(name string, value, choices []string, usage string) *[]string {
+	p := &types.Enums{Value: value, Choices: choices, Fold: true}
+	flag.Var(p, name, ChoicesUsage(usage, choices))
+	return &p.Value
+}
+
+// ChoicesUsage returns the usage string with a list of the choices
+// appended, e.g. "Level of logs (one of: debug, info, error)."
+func ChoicesUsage(usage string, choices []string) string {
+	list := "one of: " + strings.Join(choices, ", ")
+	if strings.HasSuffix(usage, ".") {
+		return strings.TrimSuffix(usage, ".") + " (" + list + ")."
+	}
+	if usage == "" {
+		return list
+	}
+	return usage + " (" + list + ")"
+}
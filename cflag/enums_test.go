@@ -0,0 +1,64 @@
+package cflag_test
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/goaltools/xflag/cflag"
+)
+
+var (
+	enum  = cflag.Enum("level", "info", []string{"debug", "info"}, "Level of logs.")
+	enums = cflag.Enums("levels[]", []string{"info"}, []string{"debug", "info"}, "Levels of logs.")
+
+	enumFold  = cflag.EnumFold("mode", "fast", []string{"fast", "safe"}, "Mode.")
+	enumsFold = cflag.EnumsFold("modes[]", nil, []string{"fast", "safe"}, "Modes.")
+)
+
+func TestEnumFuncs(t *testing.T) {
+	if *enum != "info" {
+		t.Errorf("Expected `info`, got `%v`.", *enum)
+	}
+	if exp := []string{"info"}; !reflect.DeepEqual(*enums, exp) {
+		t.Errorf("Expected `%v`, got `%v`.", exp, *enums)
+	}
+	exp := "Level of logs (one of: debug, info)."
+	if res := flag.Lookup("level").Usage; res != exp {
+		t.Errorf("Expected `%v`, got `%v`.", exp, res)
+	}
+}
+
+func TestEnumFoldFuncs(t *testing.T) {
+	if err := flag.Set("mode", "SAFE"); err != nil {
+		t.Errorf("No error expected, got `%v`.", err)
+	}
+	if *enumFold != "safe" {
+		t.Errorf("Expected `safe`, got `%v`.", *enumFold)
+	}
+	for _, v := range []string{"Fast", "safe"} {
+		if err := flag.Set("modes[]", v); err != nil {
+			t.Errorf("No error expected, got `%v`.", err)
+		}
+	}
+	if exp := []string{"fast", "safe"}; !reflect.DeepEqual(*enumsFold, exp) {
+		t.Errorf("Expected `%v`, got `%v`.", exp, *enumsFold)
+	}
+	if err := flag.Set("mode", "unsafe"); err == nil {
+		t.Errorf("Error expected, got nil.")
+	}
+}
+
+func TestChoicesUsage(t *testing.T) {
+	for _, v := range []struct {
+		usage, exp string
+	}{
+		{"", "one of: a, b"},
+		{"Choose", "Choose (one of: a, b)"},
+		{"Choose.", "Choose (one of: a, b)."},
+	} {
+		if res := cflag.ChoicesUsage(v.usage, []string{"a", "b"}); res != v.exp {
+			t.Errorf("Expected `%v`, got `%v`.", v.exp, res)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cflag
+
+import (
+	"flag"
+	"time"
+
+	"github.com/goaltools/xflag/cflag/types"
+)
+
+// StringMap defines a map flag with the specified name, default value,
+// and usage string. Values of the flag are "key=value" pairs.
+// The returned value is the address of a map[string]string
+// variable that stores the value of the flag.
+func StringMap(name string, value map[string]string, usage string) *map[string]string {
+	p := &types.StringMap{Value: value}
+	flag.Var(p, name, usage)
+	return &p.Value
+}
+
+// IntMap defines a map flag with the specified name, default value,
+// and usage string. Values of the flag are "key=value" pairs.
+// The returned value is the address of a map[string]int
+// variable that stores the value of the flag.
+func IntMap(name string, value map[string]int, usage string) *map[string]int {
+	p := &types.IntMap{Value: value}
+	flag.Var(p, name, usage)
+	return &p.Value
+}
+
+// Int64Map defines a map flag with the specified name, default value,
+// and usage string. Values of the flag are "key=value" pairs.
+// The returned value is the address of a map[string]int64
+// variable that stores the value of the flag.
+func Int64Map(name string, value map[string]int64, usage string) *map[string]int64 {
+	p := &types.Int64Map{Value: value}
+	flag.Var(p, name, usage)
+	return &p.Value
+}
+
+// UintMap defines a map flag with the specified name, default value,
+// and usage string. Values of the flag are "key=value" pairs.
+// The returned value is the address of a map[string]uint
+// variable that stores the value of the flag.
+func UintMap(name string, value map[string]uint, usage string) *map[string]uint {
+	p := &types.UintMap{Value: value}
+	flag.Var(p, name, usage)
+	return &p.Value
+}
+
+// Uint64Map defines a map flag with the specified name, default value,
+// and usage string. Values of the flag are "key=value" pairs.
+// The returned value is the address of a map[string]uint64
+// variable that stores the value of the flag.
+func Uint64Map(name string, value map[string]uint64, usage string) *map[string]uint64 {
+	p := &types.Uint64Map{Value: value}
+	flag.Var(p, name, usage)
+	return &p.Value
+}
+
+// Float64Map defines a map flag with the specified name, default value,
+// and usage string. Values of the flag are "key=value" pairs.
+// The returned value is the address of a map[string]float64
+// variable that stores the value of the flag.
+func Float64Map(name string, value map[string]float64, usage string) *map[string]float64 {
+	p := &types.Float64Map{Value: value}
+	flag.Var(p, name, usage)
+	return &p.Value
+}
+
+// BoolMap defines a map flag with the specified name, default value,
+// and usage string. Values of the flag are "key=value" pairs.
+// The returned value is the address of a map[string]bool
+// variable that stores the value of the flag.
+func BoolMap(name string, value map[string]bool, usage string) *map[string]bool {
+	p := &types.BoolMap{Value: value}
+	flag.Var(p, name, usage)
+	return &p.Value
+}
+
+// DurationMap defines a map flag with the specified name, default value,
+// and usage string. Values of the flag are "key=value" pairs.
+// The returned value is the address of a map[string]time.Duration
+// variable that stores the value of the flag.
+func DurationMap(name string, value map[string]time.Duration, usage string) *map[string]time.Duration {
+	p := &types.DurationMap{Value: value}
+	flag.Var(p, name, usage)
+	return &p.Value
+}
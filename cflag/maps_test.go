@@ -0,0 +1,52 @@
+package cflag_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/goaltools/xflag/cflag"
+)
+
+var (
+	defStrMap = map[string]string{"a": "x"}
+	strMap    = cflag.StringMap("labels{}", defStrMap, "A map of strings.")
+
+	defIntMap = map[string]int{"a": 1}
+	intMap    = cflag.IntMap("int{}", defIntMap, "A map of ints.")
+
+	defInt64Map = map[string]int64{"a": 1}
+	int64Map    = cflag.Int64Map("int64{}", defInt64Map, "A map of int64s.")
+
+	defUintMap = map[string]uint{"a": 1}
+	uintMap    = cflag.UintMap("uint{}", defUintMap, "A map of uints.")
+
+	defUint64Map = map[string]uint64{"a": 1}
+	uint64Map    = cflag.Uint64Map("uint64{}", defUint64Map, "A map of uint64s.")
+
+	defFloat64Map = map[string]float64{"a": 1.5}
+	float64Map    = cflag.Float64Map("float64{}", defFloat64Map, "A map of float64s.")
+
+	defBoolMap = map[string]bool{"a": true}
+	boolMap    = cflag.BoolMap("bool{}", defBoolMap, "A map of bools.")
+
+	defDurationMap = map[string]time.Duration{"a": time.Minute}
+	durationMap    = cflag.DurationMap("duration{}", defDurationMap, "A map of durations.")
+)
+
+func TestMapFuncs(t *testing.T) {
+	for _, v := range [][2]interface{}{
+		{defStrMap, *strMap},
+		{defIntMap, *intMap},
+		{defInt64Map, *int64Map},
+		{defUintMap, *uintMap},
+		{defUint64Map, *uint64Map},
+		{defFloat64Map, *float64Map},
+		{defBoolMap, *boolMap},
+		{defDurationMap, *durationMap},
+	} {
+		if !reflect.DeepEqual(v[0], v[1]) {
+			t.Errorf("Expected `%v`, got `%v`.", v[0], v[1])
+		}
+	}
+}
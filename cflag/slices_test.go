@@ -2,11 +2,12 @@ package cflag_test
 
 import (
 	"flag"
+	"os"
 	"reflect"
 	"testing"
 	"time"
 
-	"github.com/conveyer/xflag/cflag"
+	"github.com/goaltools/xflag/cflag"
 )
 
 var (
@@ -61,6 +62,7 @@ func TestFuncs(t *testing.T) {
 	}
 }
 
-func init() {
+func TestMain(m *testing.M) {
 	flag.Parse()
+	os.Exit(m.Run())
 }
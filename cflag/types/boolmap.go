@@ -0,0 +1,58 @@
+package types
+
+import (
+	"strconv"
+)
+
+// BoolMap represents a map of bool values with string keys,
+// a type that implements flag.Value and thus
+// can be used with flag.Var.
+type BoolMap struct {
+	base
+	Value map[string]bool
+}
+
+//
+// Methods below implement flag.Value interface.
+//
+
+// String returns the type in a human readable format.
+func (m *BoolMap) String() string { return str(m) }
+
+// Set gets a "key=value" pair and adds it to the map.
+func (m *BoolMap) Set(v string) error { return set(m, v) }
+
+//
+// Methods below implement slice interface.
+//
+
+// Len returns a number of elements in the map.
+func (m *BoolMap) length() int { return len(m.Value) }
+
+// Get returns a "key=value" pair by its index.
+func (m *BoolMap) get(i int) string { return m.pair(m.keys()[i]) }
+
+// Keys returns keys of the map in sorted order.
+func (m *BoolMap) keys() []string { return sortedKeys(m.Value) }
+
+// Pair returns a "key=value" pair by its key.
+func (m *BoolMap) pair(k string) string {
+	return k + "=" + strconv.FormatBool(m.Value[k])
+}
+
+// Alloc allocates a map of values.
+func (m *BoolMap) alloc() { m.Value = map[string]bool{} }
+
+// Add parses a "key=value" pair and adds it to the map.
+func (m *BoolMap) add(v string) error {
+	k, v, err := pair(v)
+	if err != nil {
+		return err
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return err
+	}
+	m.Value[k] = b
+	return nil
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"time"
+)
+
+// DurationMap represents a map of time.Duration values with string keys,
+// a type that implements flag.Value and thus
+// can be used with flag.Var.
+type DurationMap struct {
+	base
+	Value map[string]time.Duration
+}
+
+//
+// Methods below implement flag.Value interface.
+//
+
+// String returns the type in a human readable format.
+func (m *DurationMap) String() string { return str(m) }
+
+// Set gets a "key=value" pair and adds it to the map.
+func (m *DurationMap) Set(v string) error { return set(m, v) }
+
+//
+// Methods below implement slice interface.
+//
+
+// Len returns a number of elements in the map.
+func (m *DurationMap) length() int { return len(m.Value) }
+
+// Get returns a "key=value" pair by its index.
+func (m *DurationMap) get(i int) string { return m.pair(m.keys()[i]) }
+
+// Keys returns keys of the map in sorted order.
+func (m *DurationMap) keys() []string { return sortedKeys(m.Value) }
+
+// Pair returns a "key=value" pair by its key.
+func (m *DurationMap) pair(k string) string {
+	return k + "=" + m.Value[k].String()
+}
+
+// Alloc allocates a map of values.
+func (m *DurationMap) alloc() { m.Value = map[string]time.Duration{} }
+
+// Add parses a "key=value" pair and adds it to the map.
+func (m *DurationMap) add(v string) error {
+	k, v, err := pair(v)
+	if err != nil {
+		return err
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return err
+	}
+	m.Value[k] = d
+	return nil
+}
@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Enum represents a string value that must be one of
+// the choices, a type that implements flag.Value and
+// thus can be used with flag.Var.
+type Enum struct {
+	Value   string
+	Choices []string
+
+	// Fold is true if values must be compared with the choices
+	// case-insensitively. The matching choice is stored then.
+	Fold bool
+}
+
+//
+// Methods below implement flag.Value interface.
+//
+
+// String returns the type in a human readable format.
+func (e *Enum) String() string { return e.Value }
+
+// Set gets a string value and assigns it
+// if it is one of the choices.
+func (e *Enum) Set(v string) error {
+	c, err := choose(e.Choices, e.Fold, v)
+	if err != nil {
+		return err
+	}
+	e.Value = c
+	return nil
+}
+
+// Enums represents a slice of string values every of which
+// must be one of the choices, a type that implements
+// flag.Value and thus can be used with flag.Var.
+type Enums struct {
+	base
+	Value   []string
+	Choices []string
+
+	// Fold is true if values must be compared with the choices
+	// case-insensitively. The matching choices are stored then.
+	Fold bool
+}
+
+//
+// Methods below implement flag.Value interface.
+//
+
+// String returns the type in a human readable format.
+func (s *Enums) String() string { return str(s) }
+
+// Set gets a string value and adds it to the slice
+// if it is one of the choices.
+func (s *Enums) Set(v string) error { return set(s, v) }
+
+//
+// Methods below implement slice interface.
+//
+
+// Len returns a number of elements in the slice.
+func (s *Enums) length() int { return len(s.Value) }
+
+// Get returns a value by its index.
+func (s *Enums) get(i int) string { return s.Value[i] }
+
+// Alloc allocates a slice of values.
+func (s *Enums) alloc() { s.Value = []string{} }
+
+// Add adds a new value to the slice.
+func (s *Enums) add(v string) error {
+	c, err := choose(s.Choices, s.Fold, v)
+	if err != nil {
+		return err
+	}
+	s.Value = append(s.Value, c)
+	return nil
+}
+
+// choose returns the choice that is equal to the value.
+// If fold is true, the comparison is case-insensitive.
+func choose(choices []string, fold bool, v string) (string, error) {
+	for _, c := range choices {
+		if c == v || fold && strings.EqualFold(c, v) {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf(`"%s" is not one of "%s"`, v, strings.Join(choices, `", "`))
+}
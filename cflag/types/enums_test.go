@@ -0,0 +1,43 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnum_Set(t *testing.T) {
+	e := &Enum{Value: "info", Choices: []string{"debug", "info"}}
+	if err := e.Set("debug"); err != nil || e.String() != "debug" {
+		t.Errorf(`Expected "debug", got "%s" (%v).`, e.String(), err)
+	}
+	if err := e.Set("DEBUG"); err == nil {
+		t.Errorf("Error expected, got nil.")
+	}
+	exp := `"verbose" is not one of "debug", "info"`
+	if err := e.Set("verbose"); err == nil || err.Error() != exp {
+		t.Errorf(`Expected "%s", got "%v".`, exp, err)
+	}
+
+	e.Fold = true
+	if err := e.Set("INFO"); err != nil || e.String() != "info" {
+		t.Errorf(`Expected "info", got "%s" (%v).`, e.String(), err)
+	}
+}
+
+func TestEnums_Set(t *testing.T) {
+	s := &Enums{Value: []string{"a"}, Choices: []string{"a", "B"}, Fold: true}
+	for _, v := range []string{"b", "A"} {
+		if err := s.Set(v); err != nil {
+			t.Errorf(`"%s": No error expected, got "%v".`, v, err)
+		}
+	}
+	if exp := []string{"B", "a"}; !reflect.DeepEqual(s.Value, exp) {
+		t.Errorf(errMsg, exp, s.Value)
+	}
+	if err := s.Set("c"); err == nil {
+		t.Errorf("Error expected, got nil.")
+	}
+	if res := s.String(); res != "[B; a]" {
+		t.Errorf(errMsg, "[B; a]", res)
+	}
+}
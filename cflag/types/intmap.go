@@ -0,0 +1,58 @@
+package types
+
+import (
+	"strconv"
+)
+
+// IntMap represents a map of int values with string keys,
+// a type that implements flag.Value and thus
+// can be used with flag.Var.
+type IntMap struct {
+	base
+	Value map[string]int
+}
+
+//
+// Methods below implement flag.Value interface.
+//
+
+// String returns the type in a human readable format.
+func (m *IntMap) String() string { return str(m) }
+
+// Set gets a "key=value" pair and adds it to the map.
+func (m *IntMap) Set(v string) error { return set(m, v) }
+
+//
+// Methods below implement slice interface.
+//
+
+// Len returns a number of elements in the map.
+func (m *IntMap) length() int { return len(m.Value) }
+
+// Get returns a "key=value" pair by its index.
+func (m *IntMap) get(i int) string { return m.pair(m.keys()[i]) }
+
+// Keys returns keys of the map in sorted order.
+func (m *IntMap) keys() []string { return sortedKeys(m.Value) }
+
+// Pair returns a "key=value" pair by its key.
+func (m *IntMap) pair(k string) string {
+	return k + "=" + strconv.Itoa(m.Value[k])
+}
+
+// Alloc allocates a map of values.
+func (m *IntMap) alloc() { m.Value = map[string]int{} }
+
+// Add parses a "key=value" pair and adds it to the map.
+func (m *IntMap) add(v string) error {
+	k, v, err := pair(v)
+	if err != nil {
+		return err
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return err
+	}
+	m.Value[k] = i
+	return nil
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Map types of the package implement the slice interface,
+// so the same Set semantics are used: every call of Set adds
+// a new "key=value" pair to the map, and Set(EOI) marks the
+// map as uninitialized. Elements of maps are sorted by keys.
+
+// pair splits a "key=value" string into a key and a value.
+func pair(v string) (string, string, error) {
+	i := strings.Index(v, "=")
+	if i < 0 {
+		return "", "", fmt.Errorf(`"key=value" pair expected, got "%s"`, v)
+	}
+	return v[:i], v[i+1:], nil
+}
+
+// keyed is implemented by the map types of the package,
+// so their keys are sorted once when all the elements
+// are requested rather than for every element.
+type keyed interface {
+	keys() []string
+	pair(k string) string
+}
+
+// sortedKeys returns keys of the map in sorted order.
+func sortedKeys(m interface{}) []string {
+	ks := reflect.ValueOf(m).MapKeys()
+	ss := make([]string, len(ks))
+	for i := range ks {
+		ss[i] = ks[i].String()
+	}
+	sort.Strings(ss)
+	return ss
+}
@@ -0,0 +1,53 @@
+package types
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestSetString_Maps(t *testing.T) {
+	for _, v := range []struct {
+		v   flag.Value
+		inp []string
+		exp string
+	}{
+		{&StringMap{Value: map[string]string{"z": "0"}}, []string{"b=2", "a=1=1"}, "[a=1=1; b=2]"},
+		{&IntMap{}, []string{"x=-1", "y=2"}, "[x=-1; y=2]"},
+		{&Int64Map{}, []string{"x=-1"}, "[x=-1]"},
+		{&UintMap{}, []string{"x=1"}, "[x=1]"},
+		{&Uint64Map{}, []string{"x=1"}, "[x=1]"},
+		{&Float64Map{}, []string{"x=1.5"}, "[x=1.5]"},
+		{&BoolMap{}, []string{"x=1", "y=false"}, "[x=true; y=false]"},
+		{&DurationMap{}, []string{"x=1m"}, "[x=1m0s]"},
+		{&StringMap{}, []string{"a=1", EOI, "b=2"}, "[b=2]"},
+		{&StringMap{}, []string{"a=1", "a=2"}, "[a=2]"},
+	} {
+		for i := range v.inp {
+			if err := v.v.Set(v.inp[i]); err != nil {
+				t.Errorf(`"%s": No error expected, got "%v".`, v.inp[i], err)
+			}
+		}
+		if res := v.v.String(); res != v.exp {
+			t.Errorf(errMsg, v.exp, res)
+		}
+	}
+}
+
+func TestAdd_IncorrectInput_Maps(t *testing.T) {
+	for inp, obj := range map[string]slice{
+		"no_separator":    &StringMap{},
+		"x=int":           &IntMap{},
+		"x=int64":         &Int64Map{},
+		"x=-1":            &UintMap{},
+		"x=uint64":        &Uint64Map{},
+		"x=float64":       &Float64Map{},
+		"x=bool":          &BoolMap{},
+		"x=duration":      &DurationMap{},
+		"no_separator_at": &IntMap{},
+	} {
+		obj.alloc()
+		if err := obj.add(inp); err == nil {
+			t.Errorf(`"%s": Error expected, got nil.`, inp)
+		}
+	}
+}
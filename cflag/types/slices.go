@@ -1,5 +1,9 @@
 package types
 
+import (
+	"strings"
+)
+
 // slice is an interface that defines methods that
 // every slice type must implement.
 type slice interface {
@@ -42,11 +46,59 @@ func str(s slice) string {
 	}
 
 	// Otherwise, prepare a list and return it.
-	res := s.get(0)
-	for i := 1; i < l; i++ {
-		res += "; " + s.get(i)
+	return "[" + strings.Join(list(s), "; ") + "]"
+}
+
+// list returns all elements of the slice.
+func list(s slice) []string {
+	if m, ok := s.(keyed); ok {
+		ks := m.keys()
+		res := make([]string, len(ks))
+		for i := range ks {
+			res[i] = m.pair(ks[i])
+		}
+		return res
+	}
+	res := make([]string, s.length())
+	for i := range res {
+		res[i] = s.get(i)
+	}
+	return res
+}
+
+// Elements returns elements of the value in a human readable format
+// if it is of one of the package's slice types. False is returned
+// as a second argument otherwise.
+func Elements(v interface{}) ([]string, bool) {
+	s, ok := v.(slice)
+	if !ok {
+		return nil, false
+	}
+	return list(s), true
+}
+
+// Reset removes all elements of the value if it is of one of
+// the package's slice types. False is returned otherwise.
+func Reset(v interface{}) bool {
+	s, ok := v.(slice)
+	if ok {
+		s.alloc()
+		s.requireInit(true)
+	}
+	return ok
+}
+
+// Split gets a slice in a human readable format (as returned
+// by the String methods of the package's types) and returns its
+// elements. E.g. "[a; b; c]" is split into "a", "b", and "c".
+// NB: Elements that contain "; " cannot be restored correctly.
+func Split(s string) []string {
+	// If there are no elements in the slice,
+	// return nothing.
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' || s == "[]" {
+		return nil
 	}
-	return "[" + res + "]"
+	return strings.Split(s[1:len(s)-1], "; ")
 }
 
 func set(s slice, v string) error {
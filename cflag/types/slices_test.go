@@ -42,6 +42,44 @@ func TestStr(t *testing.T) {
 	}
 }
 
+func TestElements(t *testing.T) {
+	if res, ok := Elements(&test{d: []string{"a; b", "c"}}); !ok || !reflect.DeepEqual(res, []string{"a; b", "c"}) {
+		t.Errorf(`Expected elements of the slice, got "%v".`, res)
+	}
+	if _, ok := Elements("abc"); ok {
+		t.Errorf("Non-slice values expected to be rejected.")
+	}
+}
+
+func TestReset(t *testing.T) {
+	v := &test{d: []string{"a", "b"}}
+	if !Reset(v) || len(v.d) != 0 {
+		t.Errorf(`Expected the slice to be reset, got "%v".`, v.d)
+	}
+	set(v, "c")
+	if !reflect.DeepEqual(v.d, []string{"c"}) {
+		t.Errorf(`Expected "[c]", got "%v".`, v.d)
+	}
+	if Reset("abc") {
+		t.Errorf("Non-slice values expected to be rejected.")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	for inp, exp := range map[string][]string{
+		"":          nil,
+		"[]":        nil,
+		"abc":       nil,
+		"[a]":       {"a"},
+		"[a; b; c]": {"a", "b", "c"},
+		"[a b;c]":   {"a b;c"},
+	} {
+		if res := Split(inp); !reflect.DeepEqual(res, exp) {
+			t.Errorf(`"%s": Expected "%v", got "%v".`, inp, exp, res)
+		}
+	}
+}
+
 func TestSet(t *testing.T) {
 	for _, v := range []struct {
 		fn  func(*testing.T, *test)
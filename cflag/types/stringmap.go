@@ -0,0 +1,50 @@
+package types
+
+// StringMap represents a map of string values with string keys,
+// a type that implements flag.Value and thus
+// can be used with flag.Var.
+type StringMap struct {
+	base
+	Value map[string]string
+}
+
+//
+// Methods below implement flag.Value interface.
+//
+
+// String returns the type in a human readable format.
+func (m *StringMap) String() string { return str(m) }
+
+// Set gets a "key=value" pair and adds it to the map.
+func (m *StringMap) Set(v string) error { return set(m, v) }
+
+//
+// Methods below implement slice interface.
+//
+
+// Len returns a number of elements in the map.
+func (m *StringMap) length() int { return len(m.Value) }
+
+// Get returns a "key=value" pair by its index.
+func (m *StringMap) get(i int) string { return m.pair(m.keys()[i]) }
+
+// Keys returns keys of the map in sorted order.
+func (m *StringMap) keys() []string { return sortedKeys(m.Value) }
+
+// Pair returns a "key=value" pair by its key.
+func (m *StringMap) pair(k string) string {
+	return k + "=" + m.Value[k]
+}
+
+// Alloc allocates a map of values.
+func (m *StringMap) alloc() { m.Value = map[string]string{} }
+
+// Add parses a "key=value" pair and adds it to the map.
+func (m *StringMap) add(v string) error {
+	k, v, err := pair(v)
+	if err != nil {
+		return err
+	}
+	m.Value[k] = v
+	return nil
+}
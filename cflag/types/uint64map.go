@@ -0,0 +1,58 @@
+package types
+
+import (
+	"strconv"
+)
+
+// Uint64Map represents a map of uint64 values with string keys,
+// a type that implements flag.Value and thus
+// can be used with flag.Var.
+type Uint64Map struct {
+	base
+	Value map[string]uint64
+}
+
+//
+// Methods below implement flag.Value interface.
+//
+
+// String returns the type in a human readable format.
+func (m *Uint64Map) String() string { return str(m) }
+
+// Set gets a "key=value" pair and adds it to the map.
+func (m *Uint64Map) Set(v string) error { return set(m, v) }
+
+//
+// Methods below implement slice interface.
+//
+
+// Len returns a number of elements in the map.
+func (m *Uint64Map) length() int { return len(m.Value) }
+
+// Get returns a "key=value" pair by its index.
+func (m *Uint64Map) get(i int) string { return m.pair(m.keys()[i]) }
+
+// Keys returns keys of the map in sorted order.
+func (m *Uint64Map) keys() []string { return sortedKeys(m.Value) }
+
+// Pair returns a "key=value" pair by its key.
+func (m *Uint64Map) pair(k string) string {
+	return k + "=" + strconv.FormatUint(m.Value[k], 10)
+}
+
+// Alloc allocates a map of values.
+func (m *Uint64Map) alloc() { m.Value = map[string]uint64{} }
+
+// Add parses a "key=value" pair and adds it to the map.
+func (m *Uint64Map) add(v string) error {
+	k, v, err := pair(v)
+	if err != nil {
+		return err
+	}
+	i, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return err
+	}
+	m.Value[k] = i
+	return nil
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"strconv"
+)
+
+// UintMap represents a map of uint values with string keys,
+// a type that implements flag.Value and thus
+// can be used with flag.Var.
+type UintMap struct {
+	base
+	Value map[string]uint
+}
+
+//
+// Methods below implement flag.Value interface.
+//
+
+// String returns the type in a human readable format.
+func (m *UintMap) String() string { return str(m) }
+
+// Set gets a "key=value" pair and adds it to the map.
+func (m *UintMap) Set(v string) error { return set(m, v) }
+
+//
+// Methods below implement slice interface.
+//
+
+// Len returns a number of elements in the map.
+func (m *UintMap) length() int { return len(m.Value) }
+
+// Get returns a "key=value" pair by its index.
+func (m *UintMap) get(i int) string { return m.pair(m.keys()[i]) }
+
+// Keys returns keys of the map in sorted order.
+func (m *UintMap) keys() []string { return sortedKeys(m.Value) }
+
+// Pair returns a "key=value" pair by its key.
+func (m *UintMap) pair(k string) string {
+	return k + "=" + strconv.FormatUint(uint64(m.Value[k]), 10)
+}
+
+// Alloc allocates a map of values.
+func (m *UintMap) alloc() { m.Value = map[string]uint{} }
+
+// Add parses a "key=value" pair and adds it to the map.
+func (m *UintMap) add(v string) error {
+	k, v, err := pair(v)
+	if err != nil {
+		return err
+	}
+	i, err := strconv.ParseUint(v, 10, 0)
+	if err != nil {
+		return err
+	}
+	m.Value[k] = uint(i)
+	return nil
+}
@@ -0,0 +1,249 @@
+package xflag
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Command represents a command of a command line tool, e.g. "serve"
+// in "tool serve --port 8080". Commands may have subcommands,
+// e.g. "add" in "tool user add". Flags of a command are shared with
+// its subcommands, i.e. "tool user add --verbose" is correct if
+// "verbose" is a flag of the root command.
+type Command struct {
+	// Name is a name of the command that is used in command line
+	// arguments. Name of the root command is a name of the tool.
+	Name string
+
+	// Usage is a short description of the command.
+	Usage string
+
+	// Section is a name of the section (or object path in terms of
+	// config.Interface, elements are separated by the Separator)
+	// flags of the command are looked up in. By default, it is a list
+	// of names of the command and its parents (except the root one),
+	// e.g. flag "port" of the "tool serve" command is looked up
+	// as "serve:port".
+	Section string
+
+	// Flags is a flag set of the command.
+	Flags *flag.FlagSet
+
+	// Run is a function that is called if the command is requested.
+	// It gets positional arguments that are left after the flags.
+	// If it is nil, one of the subcommands must be requested.
+	Run func(args []string) error
+
+	// Commands is a list of subcommands.
+	Commands []*Command
+
+	parent *Command
+}
+
+// NewCommand allocates and returns a new Command with an empty flag set.
+func NewCommand(name, usage string, run func(args []string) error) *Command {
+	return &Command{
+		Name:  name,
+		Usage: usage,
+		Flags: flag.NewFlagSet(name, flag.ContinueOnError),
+		Run:   run,
+	}
+}
+
+// Add adds subcommands to the command. The command
+// is returned, so the calls can be chained.
+func (cmd *Command) Add(subs ...*Command) *Command {
+	for i := range subs {
+		subs[i].parent = cmd
+		cmd.Commands = append(cmd.Commands, subs[i])
+	}
+	return cmd
+}
+
+// Lookup returns a subcommand with the specified name
+// or nil if it doesn't exist.
+func (cmd *Command) Lookup(name string) *Command {
+	for i := range cmd.Commands {
+		if cmd.Commands[i].Name == name {
+			return cmd.Commands[i]
+		}
+	}
+	return nil
+}
+
+// FullName returns names of the command and its parents
+// separated by spaces, e.g. "tool user add".
+func (cmd *Command) FullName() string {
+	if cmd.parent == nil {
+		return cmd.Name
+	}
+	return cmd.parent.FullName() + " " + cmd.Name
+}
+
+// PrintUsage writes usage information of the command
+// including its subcommands and flags to the w.
+func (cmd *Command) PrintUsage(w io.Writer) {
+	fset := cmd.flagSet(nil)
+	fset.SetOutput(w)
+	cmd.printUsage(fset)
+}
+
+// Run finds the command requested by the arguments of the
+// Context, parses its flags and the flags of its parents using
+// ParseSet, and calls the Run function of the command.
+// Arguments that are neither flags nor names of commands
+// are passed to the Run function.
+// An error is returned if the requested command has
+// no Run function or the flags cannot be parsed.
+func (c *Context) Run(root *Command) error {
+	// Find the requested command.
+	cmd, args := c.command(root)
+	c.root = root
+
+	// Parse flags of the command and its parents.
+	fset := cmd.flagSet(c)
+	c.args = args
+	if err := c.ParseSet(fset); err != nil {
+		return err
+	}
+	if cmd.Run == nil {
+		err := fmt.Errorf(`command "%s" expects a subcommand`, cmd.FullName())
+		if fset.NArg() > 0 {
+			err = fmt.Errorf(`unknown command "%s %s"`, cmd.FullName(), fset.Arg(0))
+		}
+		fmt.Fprintln(fset.Output(), err)
+		cmd.printUsage(fset)
+		return err
+	}
+	return cmd.Run(fset.Args())
+}
+
+// command finds the command requested by the arguments of
+// the Context. Arguments without the names of commands
+// are returned as a second argument.
+func (c *Context) command(root *Command) (cmd *Command, args []string) {
+	cmd = root
+	fset := cmd.flagSet(nil)
+	for i := 0; i < len(c.args); i++ {
+		a := c.args[i]
+		switch {
+		case a == "--":
+			return cmd, append(args, c.args[i:]...)
+		case len(a) > 1 && a[0] == '-':
+			// Flags that are not boolean and are not of
+			// the "-name=value" form take the next argument.
+			args = append(args, a)
+			name := strings.TrimLeft(a, "-")
+			if strings.Contains(name, "=") {
+				continue
+			}
+			if f := fset.Lookup(name); f != nil && !isBool(f) && i+1 < len(c.args) {
+				i++
+				args = append(args, c.args[i])
+			}
+		default:
+			sub := cmd.Lookup(a)
+			if sub == nil {
+				return cmd, append(args, c.args[i:]...)
+			}
+			cmd = sub
+			fset = cmd.flagSet(nil)
+		}
+	}
+	return
+}
+
+// flagSet returns a new flag set with flags of the command and its
+// parents. Flags of subcommands have priority over flags of the
+// parents with the same names. If the Context is not nil, sections
+// of the command's flags are registered in it.
+func (cmd *Command) flagSet(c *Context) *flag.FlagSet {
+	fset := flag.NewFlagSet(cmd.FullName(), flag.ContinueOnError)
+	fset.SetOutput(cmd.Flags.Output())
+	fset.Usage = func() {
+		cmd.printUsage(fset)
+	}
+	for p := cmd; p != nil; p = p.parent {
+		var section []string
+		if c != nil {
+			section = p.section(c.Separator)
+		}
+		p.Flags.VisitAll(func(f *flag.Flag) {
+			if fset.Lookup(f.Name) != nil {
+				return
+			}
+			fset.Var(f.Value, f.Name, f.Usage)
+			fset.Lookup(f.Name).DefValue = f.DefValue
+			if len(section) > 0 {
+				c.sections[f.Name] = section
+			}
+		})
+	}
+	return fset
+}
+
+// walk calls the function for the command and all its
+// subcommands recursively. Nil command is ignored.
+func (cmd *Command) walk(fn func(*Command)) {
+	if cmd == nil {
+		return
+	}
+	fn(cmd)
+	for i := range cmd.Commands {
+		cmd.Commands[i].walk(fn)
+	}
+}
+
+// section returns the path of the section flags of
+// the command are looked up in.
+func (cmd *Command) section(sep string) []string {
+	if cmd.Section != "" {
+		return strings.Split(cmd.Section, sep)
+	}
+	if cmd.parent == nil {
+		return nil
+	}
+	return append(cmd.parent.section(sep), cmd.Name)
+}
+
+// printUsage writes usage information of the command
+// to the output of the flag set.
+func (cmd *Command) printUsage(fset *flag.FlagSet) {
+	w := fset.Output()
+	fmt.Fprintf(w, "Usage: %s [flags]", cmd.FullName())
+	if len(cmd.Commands) > 0 {
+		fmt.Fprint(w, " <command>")
+	}
+	fmt.Fprintln(w)
+	if cmd.Usage != "" {
+		fmt.Fprintf(w, "\n%s\n", cmd.Usage)
+	}
+	if len(cmd.Commands) > 0 {
+		fmt.Fprint(w, "\nCommands:\n")
+		l := 0
+		for _, sub := range cmd.Commands {
+			if len(sub.Name) > l {
+				l = len(sub.Name)
+			}
+		}
+		for _, sub := range cmd.Commands {
+			fmt.Fprintf(w, "  %-*s  %s\n", l, sub.Name, sub.Usage)
+		}
+	}
+	n := 0
+	fset.VisitAll(func(*flag.Flag) { n++ })
+	if n > 0 {
+		fmt.Fprint(w, "\nFlags:\n")
+		fset.PrintDefaults()
+	}
+}
+
+// isBool returns true if the flag doesn't require a value.
+func isBool(f *flag.Flag) bool {
+	b, ok := f.Value.(interface {
+		IsBoolFlag() bool
+	})
+	return ok && b.IsBoolFlag()
+}
@@ -0,0 +1,133 @@
+package xflag
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+type commandsResult struct {
+	cmd     string
+	args    []string
+	verbose *bool
+	port    *int
+	role    *string
+}
+
+func commands(res *commandsResult) *Command {
+	run := func(name string) func([]string) error {
+		return func(args []string) error {
+			res.cmd, res.args = name, args
+			return nil
+		}
+	}
+	root := NewCommand("tool", "A sample tool.", nil)
+	res.verbose = root.Flags.Bool("verbose", false, "verbose output")
+
+	serve := NewCommand("serve", "Start the server.", run("serve"))
+	res.port = serve.Flags.Int("port", 80, "port to listen on")
+
+	add := NewCommand("add", "Add a user.", run("user add"))
+	res.role = add.Flags.String("role", "user", "role of the user")
+
+	return root.Add(
+		serve,
+		NewCommand("user", "Manage users.", nil).Add(add),
+	)
+}
+
+func TestContext_Run(t *testing.T) {
+	for _, v := range []struct {
+		args    []string
+		cmd     string
+		pos     []string
+		verbose bool
+		port    int
+		role    string
+	}{
+		{[]string{"serve"}, "serve", nil, true, 8080, "user"},
+		{[]string{"--verbose=false", "serve", "--port", "90", "x", "y"}, "serve", []string{"x", "y"}, false, 90, "user"},
+		{[]string{"user", "add", "--role", "owner", "--verbose=0", "bob"}, "user add", []string{"bob"}, false, 8080, "owner"},
+		{[]string{"user", "add", "--", "serve"}, "user add", []string{"serve"}, true, 80, "admin"},
+	} {
+		var res commandsResult
+		root := commands(&res)
+		c := New(ini.New(nil), v.args)
+		c.Strict = true
+		if err := c.Files("./testdata/commands.ini"); err != nil {
+			t.Fatalf(`No error expected, got "%v".`, err)
+		}
+		if err := c.Run(root); err != nil {
+			t.Errorf(`%v: No error expected, got "%v".`, v.args, err)
+			continue
+		}
+		if res.cmd != v.cmd || !reflect.DeepEqual(res.args, v.pos) {
+			t.Errorf(`%v: Expected "%s %v", got "%s %v".`, v.args, v.cmd, v.pos, res.cmd, res.args)
+		}
+		if *res.verbose != v.verbose || *res.role != v.role {
+			t.Errorf(`%v: Expected "%v, %s", got "%v, %s".`, v.args, v.verbose, v.role, *res.verbose, *res.role)
+		}
+		if v.cmd == "serve" && *res.port != v.port {
+			t.Errorf(`%v: Expected port %d, got %d.`, v.args, v.port, *res.port)
+		}
+	}
+}
+
+func TestContext_Run_Errors(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"user"},
+		{"unknown"},
+		{"serve", "--unknown"},
+	} {
+		var res commandsResult
+		var buf bytes.Buffer
+		root := commands(&res)
+		root.Flags.SetOutput(&buf)
+		root.Commands[0].Flags.SetOutput(&buf)
+		root.Commands[1].Flags.SetOutput(&buf)
+		if err := New(ini.New(nil), args).Run(root); err == nil {
+			t.Errorf(`%v: Error expected, got nil.`, args)
+		}
+		if res.cmd != "" {
+			t.Errorf(`%v: No command expected to be run, got "%s".`, args, res.cmd)
+		}
+		if !strings.Contains(buf.String(), "Usage: tool") {
+			t.Errorf(`%v: Usage expected, got "%s".`, args, buf.String())
+		}
+	}
+}
+
+func TestContext_Run_Strict(t *testing.T) {
+	c := New(ini.New(nil), []string{"serve"})
+	c.Strict = true
+	if err := c.Files("./testdata/commands.ini", "./testdata/commands_unknown.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	exp := `unknown key "user:add.rol" in "./testdata/commands_unknown.ini", line 2`
+	if err := c.Run(commands(&commandsResult{})); err == nil || err.Error() != exp {
+		t.Errorf(`Expected "%s", got "%v".`, exp, err)
+	}
+}
+
+func TestCommand_PrintUsage(t *testing.T) {
+	var buf bytes.Buffer
+	commands(&commandsResult{}).Lookup("user").PrintUsage(&buf)
+	exp := `Usage: tool user [flags] <command>
+
+Manage users.
+
+Commands:
+  add  Add a user.
+
+Flags:
+  -verbose
+    	verbose output
+`
+	if res := buf.String(); res != exp {
+		t.Errorf(`Expected "%s", got "%s".`, exp, res)
+	}
+}
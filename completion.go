@@ -0,0 +1,225 @@
+package xflag
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goaltools/xflag/cflag/types"
+)
+
+// Shell is a command line shell completion scripts are generated for.
+type Shell int
+
+// Shells supported by WriteCompletion.
+const (
+	Bash Shell = iota // Bash is a script for the complete builtin of bash.
+	Zsh               // Zsh is a completion function of zsh.
+	Fish              // Fish is a list of complete commands of fish.
+)
+
+// completionFlag is a description of a flag used by WriteCompletion.
+type completionFlag struct {
+	name, typ, usage string
+	choices          []string
+	path, value      bool
+}
+
+// WriteCompletion writes a completion script of the shell for
+// the program with the flags of the flag set to the w.
+// Names of the flags are completed as they are used in command
+// line arguments, e.g. "-database:hosts[]". Values of flags of
+// types.Enum and types.Enums are completed with their choices.
+// Values of flags marked by the Path method are completed
+// with names of files. The script is generated offline
+// and doesn't call the program.
+func (c *Context) WriteCompletion(w io.Writer, fset *flag.FlagSet, shell Shell, program string) error {
+	var buf bytes.Buffer
+	fs := c.completionFlags(fset)
+	switch shell {
+	case Bash:
+		writeBash(&buf, fs, program)
+	case Zsh:
+		writeZsh(&buf, fs, program)
+	case Fish:
+		writeFish(&buf, fs, program)
+	default:
+		return fmt.Errorf(`unsupported shell "%d"`, shell)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// completionFlags returns descriptions of the flags of the flag set.
+func (c *Context) completionFlags(fset *flag.FlagSet) (res []completionFlag) {
+	fset.VisitAll(func(f *flag.Flag) {
+		typ, usage := flag.UnquoteUsage(f)
+		if i := strings.Index(usage, "\n"); i >= 0 {
+			usage = usage[:i]
+		}
+		v := f.Value
+		if s, ok := v.(*slice); ok {
+			v = s.Value
+		}
+		var choices []string
+		switch e := v.(type) {
+		case *types.Enum:
+			choices = e.Choices
+		case *types.Enums:
+			choices = e.Choices
+		}
+		res = append(res, completionFlag{
+			name:    f.Name,
+			typ:     typ,
+			usage:   usage,
+			choices: choices,
+			path:    c.marked(f.Name, filePath),
+			value:   !isBool(f),
+		})
+	})
+	return
+}
+
+// writeBash writes a completion script for bash.
+func writeBash(buf *bytes.Buffer, fs []completionFlag, program string) {
+	fn := "_" + identifier(program) + "_completion"
+	fmt.Fprintf(buf, "# bash completion for %s.\n", program)
+	fmt.Fprintf(buf, "%s() {\n", fn)
+	buf.WriteString("\tlocal cur prev\n")
+	buf.WriteString("\tcur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	buf.WriteString("\tprev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	buf.WriteString("\tif declare -F _get_comp_words_by_ref >/dev/null; then\n")
+	buf.WriteString("\t\t_get_comp_words_by_ref -n := cur prev\n")
+	buf.WriteString("\tfi\n")
+	buf.WriteString("\tcase \"$prev\" in\n")
+	var names, plain []string
+	for _, f := range fs {
+		names = append(names, "-"+f.name)
+		pattern := bashQuote("-"+f.name) + "|" + bashQuote("--"+f.name)
+		switch {
+		case len(f.choices) > 0:
+			fmt.Fprintf(buf, "\t%s)\n", pattern)
+			fmt.Fprintf(buf, "\t\tCOMPREPLY=($(compgen -W %s -- \"$cur\"))\n", bashQuote(strings.Join(f.choices, " ")))
+			buf.WriteString("\t\t;;\n")
+		case f.path:
+			fmt.Fprintf(buf, "\t%s)\n", pattern)
+			buf.WriteString("\t\tCOMPREPLY=($(compgen -f -- \"$cur\"))\n")
+			buf.WriteString("\t\t;;\n")
+		case f.value:
+			plain = append(plain, pattern)
+		}
+	}
+	if len(plain) > 0 {
+		fmt.Fprintf(buf, "\t%s)\n", strings.Join(plain, "|"))
+		buf.WriteString("\t\tCOMPREPLY=()\n")
+		buf.WriteString("\t\t;;\n")
+	}
+	buf.WriteString("\t*)\n")
+	fmt.Fprintf(buf, "\t\tCOMPREPLY=($(compgen -W %s -- \"$cur\"))\n", bashQuote(strings.Join(names, " ")))
+	buf.WriteString("\t\t;;\n")
+	buf.WriteString("\tesac\n")
+	buf.WriteString("\tif declare -F __ltrim_colon_completions >/dev/null; then\n")
+	buf.WriteString("\t\t__ltrim_colon_completions \"$cur\"\n")
+	buf.WriteString("\tfi\n")
+	buf.WriteString("}\n")
+	fmt.Fprintf(buf, "complete -F %s %s\n", fn, bashQuote(program))
+}
+
+// writeZsh writes a completion function for zsh. It may be
+// installed to the fpath or loaded using the source command.
+func writeZsh(buf *bytes.Buffer, fs []completionFlag, program string) {
+	fn := "_" + identifier(program)
+	fmt.Fprintf(buf, "#compdef %s\n\n", program)
+	fmt.Fprintf(buf, "%s() {\n", fn)
+	buf.WriteString("\t_arguments -S")
+	for _, f := range fs {
+		spec := "-" + zshEscape(f.name, `\[]:`)
+		if f.usage != "" {
+			spec += "[" + zshEscape(f.usage, `\[]`) + "]"
+		}
+		if f.value {
+			typ := f.typ
+			if typ == "" {
+				typ = "value"
+			}
+			spec += ":" + zshEscape(typ, `\:`) + ":"
+			switch {
+			case len(f.choices) > 0:
+				cs := make([]string, len(f.choices))
+				for i := range f.choices {
+					cs[i] = zshEscape(f.choices[i], `\:() `)
+				}
+				spec += "(" + strings.Join(cs, " ") + ")"
+			case f.path:
+				spec += "_files"
+			default:
+				spec += " "
+			}
+		}
+		fmt.Fprintf(buf, " \\\n\t\t%s", bashQuote(spec))
+	}
+	buf.WriteString("\n}\n\n")
+	fmt.Fprintf(buf, "if [ \"$funcstack[1]\" = %s ]; then\n", bashQuote(fn))
+	fmt.Fprintf(buf, "\t%s \"$@\"\n", fn)
+	buf.WriteString("else\n")
+	fmt.Fprintf(buf, "\tcompdef %s %s\n", fn, bashQuote(program))
+	buf.WriteString("fi\n")
+}
+
+// writeFish writes completion commands for fish.
+func writeFish(buf *bytes.Buffer, fs []completionFlag, program string) {
+	fmt.Fprintf(buf, "# fish completion for %s.\n", program)
+	for _, f := range fs {
+		fmt.Fprintf(buf, "complete -c %s -o %s", fishQuote(program), fishQuote(f.name))
+		if f.usage != "" {
+			fmt.Fprintf(buf, " -d %s", fishQuote(f.usage))
+		}
+		switch {
+		case len(f.choices) > 0:
+			fmt.Fprintf(buf, " -x -a %s", fishQuote(strings.Join(f.choices, " ")))
+		case f.path:
+			buf.WriteString(" -r -F")
+		case f.value:
+			buf.WriteString(" -x")
+		}
+		buf.WriteString("\n")
+	}
+}
+
+// identifier returns the name with all characters that are not
+// allowed in names of shell functions replaced by underscores.
+func identifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// bashQuote returns the string in single quotes of
+// bash and zsh.
+func bashQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// fishQuote returns the string in single quotes of fish.
+func fishQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
+
+// zshEscape prefixes every of the chars in the string
+// with a backslash as required by _arguments specs.
+func zshEscape(s, chars string) string {
+	var buf bytes.Buffer
+	for _, r := range s {
+		if strings.ContainsRune(chars, r) {
+			buf.WriteByte('\\')
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
@@ -0,0 +1,49 @@
+package xflag
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	"github.com/goaltools/xflag/cflag/types"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+func TestContext_WriteCompletion(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.String("name", "James", "Name of the `user`.")
+	fset.Bool("verbose", false, "Verbose output.")
+	fset.String("config", "", "Path to the configuration file.\nIt is optional.")
+	fset.Var(&types.Enum{Choices: []string{"debug", "info"}}, "log:level", "Level of [logging].")
+	fset.Var(&types.Enums{Choices: []string{"a", "b"}}, "features[]", "")
+	fset.Var(&types.Strings{}, "database:hosts[]", "List of the user's hosts.")
+
+	c := New(ini.New(nil), nil)
+	c.Path("config")
+	for _, v := range []struct {
+		shell Shell
+		file  string
+	}{
+		{Bash, "./testdata/completion.bash"},
+		{Zsh, "./testdata/completion.zsh"},
+		{Fish, "./testdata/completion.fish"},
+	} {
+		var buf bytes.Buffer
+		if err := c.WriteCompletion(&buf, fset, v.shell, "my-tool"); err != nil {
+			t.Errorf(`Shell %d: unexpected error: %v.`, v.shell, err)
+		}
+		exp, err := ioutil.ReadFile(v.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r := buf.String(); r != string(exp) {
+			t.Errorf(`Shell %d: expected "%s", got "%s".`, v.shell, exp, r)
+		}
+	}
+
+	if err := c.WriteCompletion(&bytes.Buffer{}, fset, Shell(100), "my-tool"); err == nil {
+		t.Errorf("Unsupported shell: error expected.")
+	}
+}
@@ -0,0 +1,156 @@
+// Package env implements expansion of references to environment
+// variables in values of configuration files. It is shared by all
+// the configuration formats of xflag and may be used by custom ones.
+package env
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Expand replaces references to environment variables in the string
+// by their values. It is expected to be used by implementations of
+// config.Interface for values of configuration files, so all of them
+// support the same syntax:
+//	${NAME}          - value of the variable, empty string if it is not set;
+//	${NAME:-default} - value of the variable, or the default if it is not set or empty;
+//	${NAME:?message} - value of the variable, or an error with the message
+//	                   if it is not set or empty;
+//	${NAME:+alt}     - the alt if the variable is set and not empty,
+//	                   empty string otherwise;
+//	$${NAME}         - "${NAME}" as is, no replacement is made.
+// The default, message, and alt may contain references themselves.
+// Names may consist of letters, digits, dots, underscores, and dashes.
+// References with invalid names and unterminated ones are left as is.
+// So are the escaped ones, e.g. "$${section:key}", so users of the
+// configuration that resolve references of their own, e.g. to other
+// keys, may unescape them.
+func Expand(s string) (string, error) {
+	var buf bytes.Buffer
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			buf.WriteString(s)
+			return buf.String(), nil
+		}
+
+		// Escaped "$${" is replaced by "${" if it starts
+		// a valid reference.
+		if i > 0 && s[i-1] == '$' {
+			if end := closing(s, i+2); end >= 0 {
+				if _, _, _, ok := parse(s[i+2 : end]); !ok {
+					buf.WriteString(s[:end+1])
+					s = s[end+1:]
+					continue
+				}
+			}
+			buf.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+		buf.WriteString(s[:i])
+
+		// Find the end of the reference and replace it.
+		end := closing(s, i+2)
+		if end < 0 {
+			buf.WriteString(s[i:])
+			return buf.String(), nil
+		}
+		v, ok, err := expand(s[i+2 : end])
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			v = s[i : end+1]
+		}
+		buf.WriteString(v)
+		s = s[end+1:]
+	}
+}
+
+// expand returns a value of the reference without "${" and "}".
+// False is returned as a second argument if it is not
+// a valid reference.
+func expand(ref string) (string, bool, error) {
+	name, op, word, ok := parse(ref)
+	if !ok {
+		return "", false, nil
+	}
+	v := os.Getenv(name)
+	switch op {
+	case '-':
+		if v != "" {
+			return v, true, nil
+		}
+		v, err := Expand(word)
+		return v, true, err
+	case '?':
+		if v != "" {
+			return v, true, nil
+		}
+		msg, err := Expand(word)
+		if err != nil {
+			return "", true, err
+		}
+		if msg == "" {
+			msg = "environment variable is not set"
+		}
+		return "", true, fmt.Errorf("%s: %s", name, msg)
+	case '+':
+		if v == "" {
+			return "", true, nil
+		}
+		v, err := Expand(word)
+		return v, true, err
+	}
+	return v, true, nil
+}
+
+// parse splits the reference without "${" and "}" into a name
+// of the variable, an operator ('-', '?', '+', or zero if there is
+// none), and a word. False is returned as the last argument if
+// it is not a valid reference.
+func parse(ref string) (name string, op byte, word string, ok bool) {
+	n := 0
+	for n < len(ref) && isNameChar(ref[n]) {
+		n++
+	}
+	name, rest := ref[:n], ref[n:]
+	switch {
+	case name == "":
+		return "", 0, "", false
+	case rest == "":
+		return name, 0, "", true
+	case len(rest) < 2 || rest[0] != ':' || strings.IndexByte("-?+", rest[1]) < 0:
+		return "", 0, "", false
+	}
+	return name, rest[1], rest[2:], true
+}
+
+// closing returns an index of the "}" that terminates the reference
+// starting before the beg index, or -1 if it is not terminated.
+// Nested references are skipped.
+func closing(s string, beg int) int {
+	depth := 1
+	for i := beg; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isNameChar returns true if the character is allowed
+// in names of environment variables.
+func isNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '.' || c == '_' || c == '-'
+}
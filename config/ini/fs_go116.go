@@ -0,0 +1,26 @@
+//go:build go1.16
+// +build go1.16
+
+package ini
+
+import (
+	"io/fs"
+
+	"github.com/goaltools/xflag/config/ini/internal/ini"
+
+	"github.com/conveyer/config"
+)
+
+// NewFS is an equivalent of New that reads the file with the name
+// from the file system, e.g. embed.FS. Included files are read
+// from the file system, too.
+func (c *INI) NewFS(fsys fs.FS, name string) (config.Interface, error) {
+	return newConfig(ini.OpenFS(fsys, name))
+}
+
+// JoinFS is an equivalent of Join that reads the file with the name
+// from the file system, e.g. embed.FS. Included files are read
+// from the file system, too.
+func (c *INI) JoinFS(fsys fs.FS, name string) error {
+	return c.join(ini.OpenFS(fsys, name))
+}
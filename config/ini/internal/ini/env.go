@@ -0,0 +1,26 @@
+package ini
+
+import (
+	"fmt"
+
+	"github.com/goaltools/xflag/config/env"
+	"github.com/goaltools/xflag/config/ini/internal/ini/parser"
+)
+
+// expandEnvVars replaces environment variables in the values of the
+// sections, i.e. every ${SOME_VAR} is replaced by the corresponding
+// environment variable's value. For the supported syntax, see Expand
+// of the "github.com/goaltools/xflag/config/env". The path of the
+// file the sections belong to is used in error messages.
+func expandEnvVars(ss []parser.Section, path string) error {
+	for i := range ss {
+		for j := range ss[i].Values {
+			v, err := env.Expand(string(ss[i].Values[j]))
+			if err != nil {
+				return fmt.Errorf("%s:%d: %s", path, ss[i].Lines[j], err)
+			}
+			ss[i].Values[j] = []byte(v)
+		}
+	}
+	return nil
+}
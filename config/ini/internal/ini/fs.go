@@ -0,0 +1,43 @@
+package ini
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// filesystem represents a file system configuration files
+// and the files they include are read from.
+type filesystem struct {
+	// open opens a file with the path for reading.
+	open func(path string) (io.ReadCloser, error)
+
+	// glob returns paths of the files matching the pattern.
+	glob func(pattern string) ([]string, error)
+
+	// rel returns a path of the file the including file refers to.
+	rel func(including, path string) string
+
+	// clean returns a canonical path of the file.
+	clean func(path string) string
+}
+
+// osFS is a file system of the operating system.
+var osFS = filesystem{
+	open: func(path string) (io.ReadCloser, error) {
+		return os.Open(path)
+	},
+	glob: filepath.Glob,
+	rel: func(including, path string) string {
+		if filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(filepath.Dir(including), path)
+	},
+	clean: func(path string) string {
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs
+		}
+		return filepath.Clean(path)
+	},
+}
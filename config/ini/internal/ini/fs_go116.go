@@ -0,0 +1,37 @@
+//go:build go1.16
+// +build go1.16
+
+package ini
+
+import (
+	"io"
+	"io/fs"
+	"path"
+)
+
+// OpenFS is an equivalent of OpenFileFiles that reads the file with
+// the path from the file system, e.g. embed.FS. Paths are slash-separated
+// as required by the fs package. Included files are read from the file system.
+func OpenFS(fsys fs.FS, name string) (map[string]map[string]interface{}, Lines, Files, error) {
+	r, err := newFS(fsys).openFile(name, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return r.obj, r.lines, r.files, nil
+}
+
+// newFS returns a filesystem that reads files from the fsys.
+func newFS(fsys fs.FS) filesystem {
+	return filesystem{
+		open: func(name string) (io.ReadCloser, error) {
+			return fsys.Open(name)
+		},
+		glob: func(pattern string) ([]string, error) {
+			return fs.Glob(fsys, pattern)
+		},
+		rel: func(including, name string) string {
+			return path.Join(path.Dir(including), name)
+		},
+		clean: path.Clean,
+	}
+}
@@ -0,0 +1,226 @@
+package ini
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goaltools/xflag/config/ini/internal/ini/parser"
+)
+
+// Keys of the include directives. The "@include path" form is
+// supported in every section, "include = path" only in the default
+// one, so keys named "include" of other sections are regular keys.
+const (
+	includeKey       = "include"
+	includeDirective = "@include"
+)
+
+// Files represents paths of the files keys of a parsed configuration
+// file are defined in. It has the same structure as Lines.
+type Files map[string]map[string]string
+
+// result represents a configuration file with
+// the files it includes.
+type result struct {
+	obj   config
+	lines Lines
+	files Files
+}
+
+// include represents an include directive.
+type include struct {
+	file string
+	line int
+}
+
+// chain is a list of include directives that led to a file.
+type chain []include
+
+// String returns the chain in a human readable format,
+// e.g. "a.ini:1 -> b.ini:3".
+func (ch chain) String() string {
+	ss := make([]string, len(ch))
+	for i := range ch {
+		ss[i] = fmt.Sprintf("%s:%d", ch[i].file, ch[i].line)
+	}
+	return strings.Join(ss, " -> ")
+}
+
+// openFile opens, parses, and processes the file the chain of include
+// directives led to. Files included by the file are processed in place
+// of their directives, so the values that are defined after a directive
+// override the values of the included files and vice versa, e.g.:
+//	key1 = a
+//	include = other.ini
+//	key2 = b
+// If other.ini defines key1 and key2, the result is:
+//	key1 = value of other.ini
+//	key2 = b
+// Values of included files override the previous ones the same way Join
+// method of the "github.com/goaltools/xflag/config/ini" does, i.e. arrays
+// are replaced rather than appended. Keys the included files do not
+// define are kept, so elements of an array of the including file are
+// accumulated across the directives.
+// Paths of included files are relative to the including file in the
+// file system, glob patterns, e.g. "conf.d/*.ini", are supported.
+func (fsys filesystem) openFile(path string, ch chain) (*result, error) {
+	// Make sure the file doesn't include itself.
+	for i := range ch {
+		if fsys.clean(ch[i].file) == fsys.clean(path) {
+			return nil, fmt.Errorf("include cycle: %s -> %s", ch, path)
+		}
+	}
+
+	// Try to open the requested file.
+	f, err := fsys.open(path)
+	if err != nil {
+		return nil, wrapInclude(err, path, ch)
+	}
+	defer f.Close()
+	return fsys.read(f, path, ch)
+}
+
+// wrapInclude adds the path of the included file and the chain
+// of directives that led to it to the error, if the chain is not empty.
+func wrapInclude(err error, path string, ch chain) error {
+	if len(ch) == 0 {
+		return err
+	}
+	return fmt.Errorf(`failed to include "%s" (%s): %s`, path, ch, err)
+}
+
+// read parses and processes the configuration of the file with
+// the path from the r. See openFile for details.
+func (fsys filesystem) read(rd io.Reader, path string, ch chain) (*result, error) {
+	wrap := func(err error) error {
+		return wrapInclude(err, path, ch)
+	}
+
+	// Scan and parse the file.
+	sections, err := parser.ParseReader(rd)
+	if err != nil {
+		return nil, wrap(fmt.Errorf(`failed to parse "%s": %s`, path, err))
+	}
+	if err = expandEnvVars(sections, path); err != nil {
+		return nil, wrap(err)
+	}
+
+	// Transform into the final object. Parts of the file
+	// between the include directives are processed separately.
+	c := &context{}
+	if err = c.processRefs(sections); err != nil {
+		return nil, wrap(fmt.Errorf(`failed to process "%s": %s`, path, err))
+	}
+	r := &result{obj: config{}, lines: Lines{}, files: Files{}}
+	var part []parser.Section
+	flush := func() error {
+		if err := c.processSections(part); err != nil {
+			return wrap(fmt.Errorf(`failed to process "%s": %s`, path, err))
+		}
+		r.join(&result{obj: c.obj, lines: c.lines}, path)
+		part = nil
+		return nil
+	}
+	for _, s := range sections {
+		beg := 0
+		for i := range s.Keys {
+			if !c.isInclude(s, i) {
+				continue
+			}
+			part = append(part, slice(s, beg, i))
+			beg = i + 1
+			if err := flush(); err != nil {
+				return nil, err
+			}
+
+			// Process the included files.
+			next := append(ch[:len(ch):len(ch)], include{file: path, line: s.Lines[i]})
+			paths, err := fsys.includes(path, string(s.Values[i]))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", next, err)
+			}
+			for j := range paths {
+				inc, err := fsys.openFile(paths[j], next)
+				if err != nil {
+					return nil, err
+				}
+				r.join(inc, "")
+			}
+		}
+		part = append(part, slice(s, beg, len(s.Keys)))
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// isInclude checks whether the i-th key of the section is
+// an include directive.
+func (c *context) isInclude(s parser.Section, i int) bool {
+	switch string(s.Keys[i]) {
+	case includeDirective:
+		return true
+	case includeKey:
+		return c.processSectionName(s.Name) == ""
+	}
+	return false
+}
+
+// includes returns paths of the files an include directive
+// of the file refers to. The pattern is relative to the directory
+// of the file. Patterns that match no files result in an empty list,
+// paths without wildcards must refer to existing files.
+func (fsys filesystem) includes(file, pattern string) ([]string, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("path of the included file is empty")
+	}
+	pattern = fsys.rel(file, pattern)
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+	return fsys.glob(pattern)
+}
+
+// slice returns a section with the same name and the keys
+// of the section s from beg to end.
+func slice(s parser.Section, beg, end int) parser.Section {
+	return parser.Section{
+		Name:   s.Name,
+		Keys:   s.Keys[beg:end],
+		Values: s.Values[beg:end],
+		Lines:  s.Lines[beg:end],
+	}
+}
+
+// join adds values of the child result to the r. Values of the child
+// override the existing ones. If the file is not empty, it is used
+// as a path of the files the keys of the child are defined in, and
+// arrays of the child are appended to the arrays defined in the same
+// file rather than replace them.
+func (r *result) join(child *result, file string) {
+	for n := range child.obj {
+		r.obj.allocate(n)
+		if _, ok := r.lines[n]; !ok {
+			r.lines[n] = map[string]int{}
+		}
+		if _, ok := r.files[n]; !ok {
+			r.files[n] = map[string]string{}
+		}
+		for k, v := range child.obj[n] {
+			r.lines[n][k] = child.lines[n][k]
+			if arr, ok := v.([]string); ok && file != "" && r.files[n][k] == file {
+				if prev, ok := r.obj[n][k].([]string); ok {
+					v = append(prev[:len(prev):len(prev)], arr...)
+				}
+			}
+			r.obj[n][k] = v
+			if file != "" {
+				r.files[n][k] = file
+				continue
+			}
+			r.files[n][k] = child.files[n][k]
+		}
+	}
+}
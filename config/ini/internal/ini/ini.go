@@ -0,0 +1,289 @@
+// Package ini provides functions for parsing INI configuration
+// files with extended syntax. E.g. arrays, references, and include
+// directives are supported. It is a fork of the "github.com/conveyer/ini"
+// that also reports locations of the keys.
+package ini
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goaltools/xflag/config/ini/internal/ini/parser"
+)
+
+const (
+	refKey   = "$"
+	refPref  = "&"
+	arrayLit = "[]"
+)
+
+// config represents a parsed and processed configuration
+// file. It has the following structure:
+//	section_name:
+//		key:   string_value
+//		key[]: []string_values
+type config map[string]map[string]interface{}
+
+// Lines represents numbers of the lines keys of a parsed
+// configuration file are defined on. It has the following structure:
+//	section_name:
+//		key: line_number
+// Arrays have a number of the line their last element is defined on.
+// Keys of the reference sections that are included by the
+// "$ = &section_name" syntax have the number of the line
+// where the reference is used.
+type Lines map[string]map[string]int
+
+// context implements methods for processing
+// of INI sections object and its transformation
+// into a configuration map.
+type context struct {
+	obj, refs config
+	lines     Lines
+}
+
+// allocate makes sure a map with the requested key in the config
+// is allocated.
+func (c config) allocate(n string) {
+	if _, ok := c[n]; ok {
+		return
+	}
+	c[n] = map[string]interface{}{}
+}
+
+// OpenFile gets a path to INI file, opens, parses, and returns it.
+// A non-nil error is returned as a second argument in
+// case the requested file cannot be parsed.
+func OpenFile(path string) (map[string]map[string]interface{}, error) {
+	m, _, err := OpenFileLines(path)
+	return m, err
+}
+
+// OpenFileLines is an equivalent of OpenFile that also returns
+// numbers of the lines the keys of the file are defined on.
+func OpenFileLines(path string) (map[string]map[string]interface{}, Lines, error) {
+	m, lines, _, err := OpenFileFiles(path)
+	return m, lines, err
+}
+
+// OpenFileFiles is an equivalent of OpenFileLines that also returns
+// paths of the files the keys are defined in. They differ from the path
+// for keys of the files included by "include = path" directives.
+func OpenFileFiles(path string) (map[string]map[string]interface{}, Lines, Files, error) {
+	r, err := osFS.openFile(path, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return r.obj, r.lines, r.files, nil
+}
+
+// OpenReader is an equivalent of OpenFileFiles that reads the
+// configuration from the r. The name is a logical path of the file
+// that is used in error messages and as a path of the file keys are
+// defined in. Files included by the configuration are read from disk,
+// their paths are relative to the name.
+func OpenReader(r io.Reader, name string) (map[string]map[string]interface{}, Lines, Files, error) {
+	res, err := osFS.read(r, name, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return res.obj, res.lines, res.files, nil
+}
+
+// process gets a number of INI sections returned by
+// a parser and transforms them into a configuration.
+func (c *context) process(ss []parser.Section) error {
+	// Process reference sections.
+	err := c.processRefs(ss)
+	if err != nil {
+		return err
+	}
+
+	// Process other sections.
+	return c.processSections(ss)
+}
+
+// processRefs processes special reference sections.
+// It makes sure that there are no links to other sections
+// inside them as only regular sections can use
+// "$ = &section_name" syntax.
+func (c *context) processRefs(ss []parser.Section) error {
+	// Allocate the reference config object.
+	c.refs = config{}
+
+	// Iterate over all available sections to find
+	// the reference ones.
+	for i := range ss {
+		// Ignore non-reference sections.
+		n := string(ss[i].Name)
+		if !strings.HasPrefix(n, refPref) {
+			continue
+		}
+
+		// As soon as a reference section has been found,
+		// add its key-value pairs to the config.
+		// Make sure there are no link keys inside ("false" argument).
+		c.refs.allocate(n)
+		err := c.appendKVs(c.refs[n], nil, &ss[i], false)
+		if err != nil {
+			return fmt.Errorf(
+				`reference section "%s": no references allowed, %s`, n, err,
+			)
+		}
+	}
+	return nil
+}
+
+// processSections processes regular sections and replaces
+// "$ = &section_name" key-value pairs by the key-values of the
+// respective sections. E.g. there is a configuration:
+//	section1:
+//		key1 = value1
+//		& = section2
+//	section2:
+//		key2 = value2
+// It should be transformed into:
+//	section1:
+//		key1 = value1
+//		key2 = value2
+//	section2:
+//		key2 = value2
+func (c *context) processSections(ss []parser.Section) error {
+	// Allocate the config object.
+	c.obj = config{}
+	c.lines = Lines{}
+
+	// Iterate over all available sections to find
+	// the regular ones.
+	for i := range ss {
+		// Ignore reference sections.
+		n := c.processSectionName(ss[i].Name)
+		if strings.HasPrefix(n, refPref) {
+			continue
+		}
+
+		// As soon as a regular section has been found,
+		// add its values to the config.
+		c.obj.allocate(n)
+		if _, ok := c.lines[n]; !ok {
+			c.lines[n] = map[string]int{}
+		}
+		err := c.appendKVs(c.obj[n], c.lines[n], &ss[i], true)
+		if err != nil {
+			return fmt.Errorf(
+				`section "%s": %s`, n, err,
+			)
+		}
+	}
+	return nil
+}
+
+// appendKVs gets a map and a section with pairs of keys & values.
+// It inserts the key-value pairs into the map and numbers of their
+// lines into the lines map, if it is not nil.
+func (c *context) appendKVs(m map[string]interface{}, lines map[string]int, s *parser.Section, allowRefs bool) error {
+	ks, vs := s.Keys, s.Values
+	for i := range ks {
+		// Process all of the possible errors associated with the references.
+		k := string(ks[i])
+		v := string(vs[i])
+		ok, err := c.processRef(k, v, allowRefs)
+		if err != nil {
+			return err
+		}
+		if ok {
+			// Current key-value pair is a reference and there are no
+			// any errors so far, so join the maps.
+			c.join(m, c.refs[v])
+			for k := range c.refs[v] {
+				setLine(lines, k, s.Lines[i])
+			}
+			continue
+		}
+
+		// If no array literals are presented, just add
+		// the key-value pair to the map.
+		if !strings.HasSuffix(k, arrayLit) {
+			m[k] = v
+			setLine(lines, k, s.Lines[i])
+			continue
+		}
+		// Otherwise, check whether the array has already been
+		// declared earlier. If it isn't, do it now by adding
+		// the first element.
+		k = strings.TrimSuffix(k, arrayLit) // Array literal is not a part of key's name.
+		setLine(lines, k, s.Lines[i])
+		if _, ok := m[k]; !ok {
+			m[k] = []string{v}
+			continue
+		}
+
+		// If the array element with current key has already
+		// exist, append the value.
+		m[k] = append(m[k].([]string), v)
+	}
+	return nil
+}
+
+// setLine stores the number of the line the key is defined on
+// if the lines map is not nil.
+func setLine(lines map[string]int, k string, n int) {
+	if lines != nil {
+		lines[k] = n
+	}
+}
+
+// processRef checks correctness of a reference.
+func (c *context) processRef(k, v string, allowRefs bool) (bool, error) {
+	// If this is not a reference, do nothing.
+	if k != refKey {
+		return false, nil
+	}
+
+	// Otherwise, make sure references are allowed.
+	if !allowRefs {
+		return true, fmt.Errorf(`"%s = %s" was not expected here`, k, v)
+	}
+
+	// Make sure a referenced section name starts with a "&".
+	if !strings.HasPrefix(v, refPref) {
+		return true, fmt.Errorf(`"%s = %s": a reference section was expected instead of "%s"`, k, v, v)
+	}
+
+	// Make sure a referenced section does exist.
+	if _, ok := c.refs[v]; !ok {
+		return true, fmt.Errorf(`"%s = %s": reference section "%s" does not exist`, k, v, v)
+	}
+	return true, nil
+}
+
+// join adds values of the child map to the parent one.
+// Slice objects are appended instead of being overridden. E.g.:
+//	[section1]:
+//		arr[] = a
+//		$ = &smth
+//	[&smth]
+//		arr[] = b
+//		arr[] = c
+// In the configuration above arr[] is equal to [a, b, c]
+func (c *context) join(parent, child map[string]interface{}) {
+	for k, v := range child {
+		switch v.(type) {
+		case []string:
+			parent[k] = append(parent[k].([]string), v.([]string)...)
+		default:
+			parent[k] = v
+		}
+	}
+}
+
+// processSectionName takes care unification of different variations
+// of default section.
+func (c *context) processSectionName(n []byte) string {
+	s := string(n)
+	if strings.ToLower(s) == "default" {
+		return ""
+	}
+	return s
+}
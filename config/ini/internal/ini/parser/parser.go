@@ -0,0 +1,79 @@
+// Package parser provides functions necessary for parsing
+// INI configuration format.
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+const (
+	commentBeg   = '#'
+	kvSeparator  = '='
+	sectionBeg   = '['
+	sectionEnd   = ']'
+	doubleQuote  = '"'
+	directiveBeg = '@'
+
+	includeDirective = "@include"
+)
+
+// Section represents a section of INI file.
+// It contains its name and keys along with values.
+// Lines contains numbers of the lines the keys are defined on.
+type Section struct {
+	Name         []byte
+	Keys, Values [][]byte
+	Lines        []int
+}
+
+// context represents an instance of a single parser.
+type context struct {
+	sections []Section
+	currLine int
+}
+
+// Parse gets some INI configuration as bufio.Scanner, transforms it
+// into a Go object and returns. The result is a 1:1 representation,
+// except comments are omitted.
+// No assumptions are made about what to do with repeating keys or sections
+// and other stuff like that intentionally, so this can be
+// handled on a higher layer depending on requirements.
+// If the requested configuration cannot be parsed
+// a non-nil error will be returned as a second argument.
+func Parse(s *bufio.Scanner) ([]Section, error) {
+	// Handle the input line-by-line till the end
+	// is reached.
+	c := &context{}
+	for s.Scan() {
+		c.currLine++
+		err := c.parseLine(s.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("ini syntax error on line %d: %s", c.currLine, err)
+		}
+	}
+
+	// Make sure the input has been scanned correctly.
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	// If no errors are returned so far, the input configuration
+	// has been parsed successfully. Return the result.
+	return c.sections, nil
+}
+
+// ParseReader is an equivalent of Parse that reads
+// the configuration from the r.
+func ParseReader(r io.Reader) ([]Section, error) {
+	return Parse(bufio.NewScanner(r))
+}
+
+// add appends a new key-value pair that is defined
+// on the line n to the section.
+func (s *Section) add(k, v []byte, n int) {
+	s.Keys = append(s.Keys, k)
+	s.Values = append(s.Values, v)
+	s.Lines = append(s.Lines, n)
+}
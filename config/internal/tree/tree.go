@@ -0,0 +1,262 @@
+// Package tree implements config.Interface of the "github.com/conveyer/config"
+// on top of nested maps. It is shared by the configuration formats that
+// support nested objects (JSON, YAML, etc.) so all of them
+// look up, list, and merge values the same way.
+package tree
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goaltools/xflag/config/env"
+
+	"github.com/conveyer/config"
+)
+
+// OpenFunc is a function that opens and parses a configuration file.
+// Scalar values of the result must be represented as strings,
+// arrays as []interface{}, and objects as map[string]interface{}.
+// Null values must be represented as nil.
+// Numbers of the lines the values are defined on are returned
+// as a second argument.
+type OpenFunc func(path string) (map[string]interface{}, Lines, error)
+
+// Lines represents numbers of the lines values of a configuration file
+// are defined on. Keys of the map are paths to the values
+// joined by the Key function.
+type Lines map[string]int
+
+// Key joins elements of the path into a key of Lines.
+func Key(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+// Tree is an implementation of config.Interface for
+// configurations that consist of nested objects.
+type Tree struct {
+	data   map[string]interface{}
+	lines  Lines
+	object []string
+	open   OpenFunc
+}
+
+// New allocates and returns a new Tree. The open function is used
+// by the New and Join methods for reading of configuration files.
+func New(data map[string]interface{}, open OpenFunc) *Tree {
+	return &Tree{data: data, open: open}
+}
+
+// New allocates a new configuration by parsing the
+// requested file and returns it.
+func (t *Tree) New(file string) (config.Interface, error) {
+	m, lines, err := t.read(file)
+	if err != nil {
+		return nil, err
+	}
+	c := New(m, t.open)
+	c.lines = lines
+	return c, nil
+}
+
+// Join merges a requested file with the current configuration file.
+// Values of a new file have priority over the values of the
+// current configuration. Objects are merged recursively, e.g. if
+// the config we have looks as follows:
+//	{"obj": {"key1": "value1", "key2": "value2"}}
+// and the new input configuration is:
+//	{"obj": {"key2": "another_value", "key3": "value3"}}
+// The original config is turned into:
+//	{"obj": {"key1": "value1", "key2": "another_value", "key3": "value3"}}
+// Arrays are not merged, the ones of the new file replace the old values.
+func (t *Tree) Join(file string) error {
+	// Open the requested configuration file and parse it.
+	m, lines, err := t.read(file)
+	if err != nil {
+		return err
+	}
+
+	// If current configuration data hasn't been
+	// allocated yet, do it now.
+	if t.data == nil {
+		t.data = map[string]interface{}{}
+	}
+	if t.lines == nil {
+		t.lines = Lines{}
+	}
+	Merge(t.data, m)
+	for k := range lines {
+		t.lines[k] = lines[k]
+	}
+	return nil
+}
+
+// read opens and parses the requested file and replaces
+// environment variables in its string values.
+// For the supported syntax, see env.Expand.
+func (t *Tree) read(file string) (map[string]interface{}, Lines, error) {
+	m, lines, err := t.open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, err = expand(m, nil, func(p []string, err error) error {
+		return fmt.Errorf("%s:%d: %s", file, lines.line(p), err)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, lines, nil
+}
+
+// expand replaces environment variables in the strings of the value
+// with the path and returns the result. Arrays and objects are
+// processed in place recursively. The fail function builds errors.
+func expand(v interface{}, p []string, fail func([]string, error) error) (interface{}, error) {
+	var err error
+	switch v := v.(type) {
+	case string:
+		s, err := env.Expand(v)
+		if err != nil {
+			return nil, fail(p, err)
+		}
+		return s, nil
+	case map[string]interface{}:
+		for k := range v {
+			if v[k], err = expand(v[k], append(p[:len(p):len(p)], k), fail); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			if v[i], err = expand(v[i], append(p[:len(p):len(p)], strconv.Itoa(i)), fail); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
+
+// At defines an object where Value method will retrieve values from.
+// Every element of the objectPath is a name of a nested object.
+// Subsequent calls of At are relative to the previously selected object.
+// For illustration, there is a configuration:
+//	{"users": {"admins": {"root": {"email": "abc@xyz.xx"}}}}
+// The code below extracts the e-mail:
+//	c.At("users", "admins").Value("root", "email") // abc@xyz.xx
+func (t *Tree) At(objectPath ...string) config.Interface {
+	c := New(t.data, t.open)
+	c.lines = t.lines
+	c.object = t.path(objectPath)
+	return c
+}
+
+// Value retrieves a value by its path relative to the current object.
+// Scalars are returned as strings, arrays of scalars are returned
+// as []string. A nil value is returned if there is
+// no such key or the value is null.
+func (t *Tree) Value(elementPath ...string) config.ValueInterface {
+	v, ok := Get(t.data, t.path(elementPath))
+	if !ok {
+		return config.NewValue(nil)
+	}
+	return config.NewValue(convert(v))
+}
+
+// Line returns a number of the line the value located by the
+// elementPath relative to the current object is defined on.
+// If the line of the value is unknown, the line of the closest
+// parent object is returned. Zero is returned if there is no such.
+func (t *Tree) Line(elementPath ...string) int {
+	return t.lines.line(t.path(elementPath))
+}
+
+// line returns a number of the line the value with the path
+// or its closest parent object is defined on.
+func (ls Lines) line(p []string) int {
+	for i := len(p); i > 0; i-- {
+		if n, ok := ls[Key(p[:i])]; ok {
+			return n
+		}
+	}
+	return 0
+}
+
+// Names returns a sorted list of keys of the object that is
+// located by the objectPath relative to the current object.
+func (t *Tree) Names(objectPath ...string) []string {
+	v, ok := Get(t.data, t.path(objectPath))
+	if !ok {
+		return nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	lst := make([]string, 0, len(m))
+	for k := range m {
+		lst = append(lst, k)
+	}
+	sort.Strings(lst)
+	return lst
+}
+
+// path returns a full path to the element that is
+// located by the p relative to the current object.
+func (t *Tree) path(p []string) []string {
+	return append(append([]string{}, t.object...), p...)
+}
+
+// Get returns an element of the data located by the path.
+// False is returned as a second argument if there is no such
+// element or it is nil.
+func Get(data map[string]interface{}, path []string) (interface{}, bool) {
+	var v interface{} = data
+	for i := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[path[i]]; !ok {
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+// Merge adds values of the child map to the parent one.
+// Nested maps are merged recursively, all other values
+// of the parent are overridden.
+func Merge(parent, child map[string]interface{}) {
+	for k, v := range child {
+		cm, ok := v.(map[string]interface{})
+		if !ok {
+			parent[k] = v
+			continue
+		}
+		pm, ok := parent[k].(map[string]interface{})
+		if !ok {
+			pm = map[string]interface{}{}
+			parent[k] = pm
+		}
+		Merge(pm, cm)
+	}
+}
+
+// convert transforms arrays of strings into []string.
+// Values of other types are returned as is.
+func convert(v interface{}) interface{} {
+	a, ok := v.([]interface{})
+	if !ok {
+		return v
+	}
+	ss := make([]string, len(a))
+	for i := range a {
+		s, ok := a[i].(string)
+		if !ok {
+			return v
+		}
+		ss[i] = s
+	}
+	return ss
+}
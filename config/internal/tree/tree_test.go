@@ -0,0 +1,81 @@
+package tree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func open(path string) (map[string]interface{}, Lines, error) {
+	switch path {
+	case "file1":
+		return map[string]interface{}{
+			"key1":  "value1",
+			"list":  []interface{}{"a", "b"},
+			"mixed": []interface{}{"a", map[string]interface{}{}},
+			"null":  nil,
+			"obj": map[string]interface{}{
+				"key2": "value2",
+				"nested": map[string]interface{}{
+					"key3": "value3",
+				},
+			},
+		}, Lines{"key1": 1, Key([]string{"obj"}): 2, Key([]string{"obj", "key2"}): 3}, nil
+	case "file2":
+		return map[string]interface{}{
+			"list": []interface{}{"c"},
+			"obj": map[string]interface{}{
+				"nested": map[string]interface{}{
+					"key4": "value4",
+				},
+			},
+		}, Lines{Key([]string{"obj", "key2"}): 4}, nil
+	}
+	return nil, nil, errors.New("file does not exist")
+}
+
+func TestTree(t *testing.T) {
+	c, err := New(nil, open).New("file1")
+	if err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.Join("file2"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+
+	for _, v := range []struct {
+		val, exp interface{}
+	}{
+		{c.Value("key1").Interface(), "value1"},
+		{c.Value("list").Interface(), []string{"c"}},
+		{c.Value("mixed").Interface(), []interface{}{"a", map[string]interface{}{}}},
+		{c.Value("null").Interface(), nil},
+		{c.Value("key1", "smth").Interface(), nil},
+		{c.At("obj").Value("key2").Interface(), "value2"},
+		{c.At("obj").Value("nested", "key3").Interface(), "value3"},
+		{c.At("obj", "nested").Value("key4").Interface(), "value4"},
+		{c.At("obj").At("nested").Value("key4").Interface(), "value4"},
+		{c.Names(), []string{"key1", "list", "mixed", "null", "obj"}},
+		{c.Names("obj", "nested"), []string{"key3", "key4"}},
+		{c.At("obj").Names(), []string{"key2", "nested"}},
+		{c.Names("key1"), []string(nil)},
+		{c.Names("doesNotExist"), []string(nil)},
+		{c.(*Tree).Line("key1"), 1},
+		{c.At("obj").(*Tree).Line("key2"), 4},
+		{c.At("obj").(*Tree).Line("nested", "key4"), 2},
+		{c.(*Tree).Line("list"), 0},
+	} {
+		if !reflect.DeepEqual(v.val, v.exp) {
+			t.Errorf(`Expected "%v", got "%v".`, v.exp, v.val)
+		}
+	}
+}
+
+func TestTree_IncorrectFile(t *testing.T) {
+	if _, err := New(nil, open).New("doesNotExist"); err == nil {
+		t.Errorf("Error expected, got nil.")
+	}
+	if err := New(nil, open).Join("doesNotExist"); err == nil {
+		t.Errorf("Error expected, got nil.")
+	}
+}
@@ -0,0 +1,146 @@
+// Package json provides a type that implements Interface of the
+// "github.com/conveyer/config" for the JSON configuration format.
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/goaltools/xflag/config/internal/tree"
+)
+
+// JSON is an implementation of config.Interface for json
+// configuration files.
+// Nested objects are used as sections, i.e. a flag "a:b:c"
+// is looked up in the following document:
+//	{"a": {"b": {"c": "value"}}}
+// Numbers and booleans are returned as strings. Arrays of them
+// are returned as []string by Strings method of the values, so they
+// can be used with slice flags of xflag/cflag package.
+// Objects of joined files are merged recursively.
+type JSON struct {
+	*tree.Tree
+}
+
+// New allocates and returns a new JSON type.
+func New(data map[string]interface{}) *JSON {
+	return &JSON{tree.New(data, openFile)}
+}
+
+// openFile gets a path to JSON file, opens, parses, and returns it.
+// The root of the document is expected to be an object.
+func openFile(path string) (map[string]interface{}, tree.Lines, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, lines, err := decode(bs)
+	if err != nil {
+		return nil, nil, fmt.Errorf(`failed to parse "%s": %s`, path, err)
+	}
+	return m, lines, nil
+}
+
+// decoder represents a state of JSON document decoding.
+type decoder struct {
+	*json.Decoder
+	data  []byte
+	lines tree.Lines
+
+	off, line int // The last known offset and its line number.
+}
+
+// decode parses a JSON document and returns it. Numbers and booleans
+// are represented as strings. Numbers of the lines keys and array
+// elements are defined on are returned as a second argument.
+func decode(data []byte) (map[string]interface{}, tree.Lines, error) {
+	// Numbers are decoded as json.Number so they are
+	// converted to strings without any loss of precision.
+	d := &decoder{Decoder: json.NewDecoder(bytes.NewReader(data)), data: data, lines: tree.Lines{}, line: 1}
+	d.UseNumber()
+
+	v, err := d.value(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, nil, errors.New("root of the document must be an object")
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, nil, fmt.Errorf("unexpected data after the root object on line %d", d.current())
+	}
+	return m, d.lines, nil
+}
+
+// value decodes a value that is located by the path.
+func (d *decoder) value(path []string) (interface{}, error) {
+	t, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := t.(type) {
+	case json.Delim:
+		if v == '{' {
+			return d.object(path)
+		}
+		return d.array(path)
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		if v {
+			return "true", nil
+		}
+		return "false", nil
+	}
+	return t, nil // A string or nil.
+}
+
+// object decodes members of an object that is located by the path.
+func (d *decoder) object(path []string) (interface{}, error) {
+	m := map[string]interface{}{}
+	for d.More() {
+		t, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		k := t.(string)
+		p := append(append([]string{}, path...), k)
+		d.lines[tree.Key(p)] = d.current()
+		if m[k], err = d.value(p); err != nil {
+			return nil, err
+		}
+	}
+	_, err := d.Token() // Closing brace.
+	return m, err
+}
+
+// array decodes elements of an array that is located by the path.
+func (d *decoder) array(path []string) (interface{}, error) {
+	lst := []interface{}{}
+	for d.More() {
+		p := append(append([]string{}, path...), strconv.Itoa(len(lst)))
+		d.lines[tree.Key(p)] = d.current()
+		v, err := d.value(p)
+		if err != nil {
+			return nil, err
+		}
+		lst = append(lst, v)
+	}
+	_, err := d.Token() // Closing bracket.
+	return lst, err
+}
+
+// current returns a number of the line the last
+// decoded token is located on.
+func (d *decoder) current() int {
+	off := int(d.InputOffset())
+	d.line += bytes.Count(d.data[d.off:off], []byte("\n"))
+	d.off = off
+	return d.line
+}
@@ -0,0 +1,50 @@
+package json
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	c, err := New(nil).New("./testdata/file1.json")
+	if err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.Join("./testdata/file2.json"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+
+	for _, v := range []struct {
+		val, exp interface{}
+	}{
+		{c.Value("key1").Interface(), "value1"},
+		{c.Value("port").Interface(), "9090"},
+		{c.Value("debug").Interface(), "true"},
+		{c.Value("nothing").Interface(), nil},
+		{c.Value("names").Interface(), []string{"Bob"}},
+		{c.Value("flags").Interface(), []string{"true", "false", "1.5"}},
+		{c.At("database").Value("user").Interface(), "root"},
+		{c.At("database").Value("primary", "host").Interface(), "example.com"},
+		{c.At("database", "primary").Value("port").Interface(), "28015"},
+		{c.Names("database"), []string{"primary", "user"}},
+	} {
+		if !reflect.DeepEqual(v.val, v.exp) {
+			t.Errorf(`Expected "%v", got "%v".`, v.exp, v.val)
+		}
+	}
+
+	if _, ok := c.Value("mixed").Strings(); ok {
+		t.Errorf("Array of objects cannot be represented as []string.")
+	}
+}
+
+func TestJSON_IncorrectFile(t *testing.T) {
+	for _, f := range []string{"./testdata/invalid.json", "./testdata/trailing.json", "./testdata/doesNotExist.json"} {
+		if _, err := New(nil).New(f); err == nil {
+			t.Errorf(`"%s": Error expected, got nil.`, f)
+		}
+		if err := New(nil).Join(f); err == nil {
+			t.Errorf(`"%s": Error expected, got nil.`, f)
+		}
+	}
+}
@@ -0,0 +1,660 @@
+package toml
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goaltools/xflag/config/internal/tree"
+)
+
+// parser represents an instance of a single TOML parser.
+type parser struct {
+	s    string
+	i    int
+	line int
+
+	root    map[string]interface{}
+	cur     map[string]interface{}
+	curPath []string
+	defined map[string]bool
+	nums    tree.Lines
+}
+
+// tableArray represents an array of tables, i.e. "[[name]]",
+// while the document is being parsed.
+type tableArray struct {
+	tables []map[string]interface{}
+}
+
+// parse gets a TOML document, transforms it into a Go object and returns.
+// Tables are represented as map[string]interface{}, arrays as
+// []interface{}. Values of all other types are represented as strings:
+// integers are converted to decimal notation, date-times use "T"
+// as a separator of date and time.
+// Numbers of the lines keys and tables are defined on are
+// returned as a second argument.
+func parse(data string) (map[string]interface{}, tree.Lines, error) {
+	p := &parser{
+		s:    strings.TrimPrefix(data, "\ufeff"),
+		line: 1,
+
+		root:    map[string]interface{}{},
+		defined: map[string]bool{},
+		nums:    tree.Lines{},
+	}
+	p.cur = p.root
+	if err := p.document(); err != nil {
+		return nil, nil, fmt.Errorf("toml syntax error on line %d: %s", p.line, err)
+	}
+	return normalize(p.root).(map[string]interface{}), p.nums, nil
+}
+
+// document parses statements of the document till its end.
+func (p *parser) document() error {
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(p.s[p.i:], "[["):
+			err = p.tableArrayHeader()
+		case p.s[p.i] == '[':
+			err = p.tableHeader()
+		default:
+			err = p.keyValue(p.cur, p.curPath)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Every statement must be followed by the end of the line.
+		p.skipSpace()
+		p.skipComment()
+		if !p.eof() && !p.newline() {
+			return fmt.Errorf(`end of line expected near "%s"`, p.rest())
+		}
+	}
+}
+
+// tableHeader parses a "[table]" header and makes
+// the table current.
+func (p *parser) tableHeader() error {
+	p.i++
+	key, err := p.key()
+	if err != nil {
+		return err
+	}
+	if !p.consume("]") {
+		return fmt.Errorf(`"]" expected near "%s"`, p.rest())
+	}
+
+	// Tables cannot be defined more than once.
+	name := strings.Join(quoteKey(key), ".")
+	if p.defined[name] {
+		return fmt.Errorf(`table "%s" is already defined`, name)
+	}
+	p.defined[name] = true
+
+	t, err := p.table(p.root, key)
+	if err != nil {
+		return err
+	}
+	p.cur, p.curPath = t, key
+	p.nums[tree.Key(key)] = p.line
+	return nil
+}
+
+// tableArrayHeader parses a "[[table]]" header, appends a new table
+// to the array and makes it current.
+func (p *parser) tableArrayHeader() error {
+	p.i += 2
+	key, err := p.key()
+	if err != nil {
+		return err
+	}
+	if !p.consume("]]") {
+		return fmt.Errorf(`"]]" expected near "%s"`, p.rest())
+	}
+
+	parent, err := p.table(p.root, key[:len(key)-1])
+	if err != nil {
+		return err
+	}
+	k := key[len(key)-1]
+	switch parent[k].(type) {
+	case nil:
+		parent[k] = &tableArray{}
+	case *tableArray:
+	default:
+		return fmt.Errorf(`key "%s" is already defined and is not an array of tables`, k)
+	}
+
+	// Nested tables of the previous element of the array
+	// can be redefined by the new one.
+	name := strings.Join(quoteKey(key), ".")
+	for n := range p.defined {
+		if strings.HasPrefix(n, name+".") {
+			delete(p.defined, n)
+		}
+	}
+
+	a := parent[k].(*tableArray)
+	a.tables = append(a.tables, map[string]interface{}{})
+	p.cur = a.tables[len(a.tables)-1]
+	p.curPath = append(key, strconv.Itoa(len(a.tables)-1))
+	p.nums[tree.Key(p.curPath)] = p.line
+	return nil
+}
+
+// table returns a table located by the path relative to the t.
+// Tables that do not exist are created. The last element of
+// an array of tables is used if the path goes through it.
+func (p *parser) table(t map[string]interface{}, path []string) (map[string]interface{}, error) {
+	for _, k := range path {
+		switch v := t[k].(type) {
+		case nil:
+			m := map[string]interface{}{}
+			t[k] = m
+			t = m
+		case map[string]interface{}:
+			t = v
+		case *tableArray:
+			t = v.tables[len(v.tables)-1]
+		default:
+			return nil, fmt.Errorf(`key "%s" is already defined and is not a table`, k)
+		}
+	}
+	return t, nil
+}
+
+// keyValue parses a "key = value" statement and adds it
+// to the table that is located by the path.
+func (p *parser) keyValue(t map[string]interface{}, path []string) error {
+	key, err := p.key()
+	if err != nil {
+		return err
+	}
+	path = append(append([]string{}, path...), key...)
+	p.nums[tree.Key(path)] = p.line
+	if p.skipSpace(); !p.consume("=") {
+		return fmt.Errorf(`"=" expected after the key "%s"`, strings.Join(quoteKey(key), "."))
+	}
+	p.skipSpace()
+	v, err := p.value(path)
+	if err != nil {
+		return err
+	}
+
+	// Dotted keys define nested tables.
+	t, err = p.table(t, key[:len(key)-1])
+	if err != nil {
+		return err
+	}
+	k := key[len(key)-1]
+	if _, ok := t[k]; ok {
+		return fmt.Errorf(`duplicate key "%s"`, k)
+	}
+	t[k] = v
+	return nil
+}
+
+// key parses a bare, quoted, or dotted key.
+func (p *parser) key() ([]string, error) {
+	var key []string
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return nil, errors.New("key expected")
+		}
+
+		var k string
+		var err error
+		switch p.s[p.i] {
+		case '"':
+			k, err = p.basicString()
+		case '\'':
+			k, err = p.literalString()
+		default:
+			beg := p.i
+			for !p.eof() && isBare(p.s[p.i]) {
+				p.i++
+			}
+			if k = p.s[beg:p.i]; k == "" {
+				return nil, fmt.Errorf(`key expected near "%s"`, p.rest())
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		key = append(key, k)
+
+		if p.skipSpace(); !p.consume(".") {
+			return key, nil
+		}
+	}
+}
+
+// value parses a value of any type that is located by the path.
+func (p *parser) value(path []string) (interface{}, error) {
+	if p.eof() {
+		return nil, errors.New("value expected")
+	}
+	switch rest := p.s[p.i:]; true {
+	case strings.HasPrefix(rest, `"""`):
+		return p.multilineBasicString()
+	case strings.HasPrefix(rest, "'''"):
+		return p.multilineLiteralString()
+	case rest[0] == '"':
+		return p.basicString()
+	case rest[0] == '\'':
+		return p.literalString()
+	case rest[0] == '[':
+		return p.array(path)
+	case rest[0] == '{':
+		return p.inlineTable(path)
+	}
+	return p.scalar()
+}
+
+// array parses an array value that may span multiple lines.
+func (p *parser) array(path []string) (interface{}, error) {
+	p.i++
+	lst := []interface{}{}
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil, errors.New("array is not terminated")
+		}
+		if p.consume("]") {
+			return lst, nil
+		}
+		i := strconv.Itoa(len(lst))
+		v, err := p.value(append(append([]string{}, path...), i))
+		if err != nil {
+			return nil, err
+		}
+		lst = append(lst, v)
+
+		p.skipBlank()
+		if !p.consume(",") && (p.eof() || p.s[p.i] != ']') {
+			return nil, fmt.Errorf(`"," or "]" expected near "%s"`, p.rest())
+		}
+	}
+}
+
+// inlineTable parses an inline table, i.e. "{a = 1, b = 2}",
+// that is located by the path.
+func (p *parser) inlineTable(path []string) (interface{}, error) {
+	p.i++
+	m := map[string]interface{}{}
+	if p.skipSpace(); p.consume("}") {
+		return m, nil
+	}
+	for {
+		if err := p.keyValue(m, path); err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		switch {
+		case p.consume("}"):
+			return m, nil
+		case !p.consume(","):
+			return nil, fmt.Errorf(`"," or "}" expected near "%s"`, p.rest())
+		}
+	}
+}
+
+var (
+	// datetime matches offset and local date-times, local dates and times.
+	datetime = regexp.MustCompile(
+		`^(\d{4}-\d{2}-\d{2}([Tt ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?([Zz]|[+-]\d{2}:\d{2})?)?|\d{2}:\d{2}(:\d{2}(\.\d+)?)?)`,
+	)
+
+	// number matches integers and floats in all the supported notations.
+	number = regexp.MustCompile(`^[+-]?[0-9A-Za-z_.]+([+-][0-9_]+)?`)
+)
+
+// scalar parses a boolean, number, or date-time.
+func (p *parser) scalar() (interface{}, error) {
+	rest := p.s[p.i:]
+	for _, b := range []string{"true", "false"} {
+		if strings.HasPrefix(rest, b) && (len(rest) == len(b) || !isBare(rest[len(b)])) {
+			p.i += len(b)
+			return b, nil
+		}
+	}
+
+	// Date-times are returned in RFC 3339 format.
+	if d := datetime.FindString(rest); d != "" {
+		p.i += len(d)
+		if len(d) > 10 && (d[10] == ' ' || d[10] == 't') {
+			d = d[:10] + "T" + d[11:]
+		}
+		return d, nil
+	}
+
+	n := number.FindString(rest)
+	if n == "" {
+		return nil, fmt.Errorf(`value expected near "%s"`, p.rest())
+	}
+	p.i += len(n)
+	return parseNumber(n)
+}
+
+// parseNumber validates an integer or float and
+// returns its string representation.
+func parseNumber(n string) (string, error) {
+	s := n
+	if strings.Contains(s, "_") {
+		if strings.HasPrefix(s, "_") || strings.HasSuffix(s, "_") || strings.Contains(s, "__") {
+			return "", fmt.Errorf(`invalid number "%s"`, n)
+		}
+		s = strings.Replace(s, "_", "", -1)
+	}
+
+	// Integers in hexadecimal, octal, and binary notations.
+	for p, base := range map[string]int{"0x": 16, "0o": 8, "0b": 2} {
+		if strings.HasPrefix(s, p) {
+			i, err := strconv.ParseInt(s[2:], base, 64)
+			if err != nil {
+				return "", fmt.Errorf(`invalid number "%s"`, n)
+			}
+			return strconv.FormatInt(i, 10), nil
+		}
+	}
+
+	// Special float values.
+	switch strings.TrimLeft(s, "+-") {
+	case "inf":
+		return strings.TrimPrefix(s, "+"), nil
+	case "nan":
+		return "NaN", nil
+	}
+
+	// Decimal integers and floats. Leading zeros are not allowed.
+	d := strings.TrimLeft(s, "+-")
+	if len(d) > 1 && d[0] == '0' && d[1] != '.' && d[1] != 'e' && d[1] != 'E' {
+		return "", fmt.Errorf(`invalid number "%s"`, n)
+	}
+	var err error
+	switch {
+	case !strings.ContainsAny(d, ".eE"):
+		_, err = strconv.ParseInt(s, 10, 64)
+	case strings.HasPrefix(d, ".") || strings.Contains(d, ".e") || strings.Contains(d, ".E") || strings.HasSuffix(d, "."):
+		err = errors.New("digits around the decimal point expected")
+	default:
+		var f float64
+		if f, err = strconv.ParseFloat(s, 64); err == nil && math.IsInf(f, 0) {
+			err = errors.New("out of range")
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf(`invalid number "%s"`, n)
+	}
+	return strings.TrimPrefix(s, "+"), nil
+}
+
+// basicString parses a string in double quotes.
+func (p *parser) basicString() (string, error) {
+	var res []byte
+	for p.i++; !p.eof(); p.i++ {
+		switch c := p.s[p.i]; c {
+		case '"':
+			p.i++
+			return string(res), nil
+		case '\n':
+			return "", errors.New("basic string cannot contain new lines")
+		case '\\':
+			bs, err := p.escape()
+			if err != nil {
+				return "", err
+			}
+			res = append(res, bs...)
+		default:
+			res = append(res, c)
+		}
+	}
+	return "", errors.New("string is not terminated")
+}
+
+// multilineBasicString parses a string in triple double quotes.
+func (p *parser) multilineBasicString() (string, error) {
+	p.i += 3
+	p.newline() // The first new line is trimmed.
+
+	var res []byte
+	for ; !p.eof(); p.i++ {
+		switch c := p.s[p.i]; true {
+		case strings.HasPrefix(p.s[p.i:], `"""`):
+			// Up to two additional quotes are a part of the string.
+			p.i += 3
+			for n := 0; n < 2 && p.consume(`"`); n++ {
+				res = append(res, '"')
+			}
+			return string(res), nil
+		case c == '\\' && p.lineEndingBackslash():
+			p.i--
+		case c == '\\':
+			bs, err := p.escape()
+			if err != nil {
+				return "", err
+			}
+			res = append(res, bs...)
+		case c == '\n':
+			p.line++
+			res = append(res, c)
+		default:
+			res = append(res, c)
+		}
+	}
+	return "", errors.New("multiline string is not terminated")
+}
+
+// lineEndingBackslash checks whether the current backslash is the
+// last non-space character of the line. If so, it skips the
+// backslash and all the spaces including new lines that follow it.
+func (p *parser) lineEndingBackslash() bool {
+	j := p.i + 1
+	for j < len(p.s) && (p.s[j] == ' ' || p.s[j] == '\t') {
+		j++
+	}
+	if j == len(p.s) || p.s[j] != '\n' && !strings.HasPrefix(p.s[j:], "\r\n") {
+		return false
+	}
+	p.i = j
+	p.skipBlankNoComments()
+	return true
+}
+
+// literalString parses a string in single quotes.
+func (p *parser) literalString() (string, error) {
+	p.i++
+	end := strings.IndexAny(p.s[p.i:], "'\n")
+	if end < 0 || p.s[p.i+end] != '\'' {
+		return "", errors.New("literal string is not terminated")
+	}
+	s := p.s[p.i : p.i+end]
+	p.i += end + 1
+	return s, nil
+}
+
+// multilineLiteralString parses a string in triple single quotes.
+func (p *parser) multilineLiteralString() (string, error) {
+	p.i += 3
+	p.newline() // The first new line is trimmed.
+
+	end := strings.Index(p.s[p.i:], "'''")
+	if end < 0 {
+		return "", errors.New("multiline literal string is not terminated")
+	}
+
+	// Up to two additional quotes are a part of the string.
+	for n := 0; n < 2 && p.i+end+3 < len(p.s) && p.s[p.i+end+3] == '\''; n++ {
+		end++
+	}
+	s := p.s[p.i : p.i+end]
+	p.line += strings.Count(s, "\n")
+	p.i += end + 3
+	return s, nil
+}
+
+// escape processes an escape sequence that starts at the
+// current position and moves the position to its last character.
+func (p *parser) escape() ([]byte, error) {
+	if p.i++; p.eof() {
+		return nil, errors.New("incomplete escape sequence")
+	}
+	size := 0
+	switch c := p.s[p.i]; c {
+	case 'b':
+		return []byte{'\b'}, nil
+	case 't':
+		return []byte{'\t'}, nil
+	case 'n':
+		return []byte{'\n'}, nil
+	case 'f':
+		return []byte{'\f'}, nil
+	case 'r':
+		return []byte{'\r'}, nil
+	case 'e':
+		return []byte{0x1b}, nil
+	case '"', '\\':
+		return []byte{c}, nil
+	case 'u':
+		size = 4
+	case 'U':
+		size = 8
+	default:
+		return nil, fmt.Errorf(`unknown escape sequence "\%c"`, c)
+	}
+	if p.i+size >= len(p.s) {
+		return nil, errors.New("incomplete escape sequence")
+	}
+	r, err := strconv.ParseUint(p.s[p.i+1:p.i+1+size], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(r)) {
+		return nil, fmt.Errorf(`invalid escape sequence "\%s"`, p.s[p.i:p.i+1+size])
+	}
+	p.i += size
+	var buf [utf8.UTFMax]byte
+	return buf[:utf8.EncodeRune(buf[:], rune(r))], nil
+}
+
+// eof returns true if the whole input has been processed.
+func (p *parser) eof() bool {
+	return p.i >= len(p.s)
+}
+
+// rest returns the rest of the current line for use in error messages.
+func (p *parser) rest() string {
+	s := p.s[p.i:]
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimRight(s, "\r")
+}
+
+// consume moves the position after the prefix if
+// the input at the position starts with it.
+func (p *parser) consume(prefix string) bool {
+	if strings.HasPrefix(p.s[p.i:], prefix) {
+		p.i += len(prefix)
+		return true
+	}
+	return false
+}
+
+// newline consumes a new line and returns true
+// if the current position points to it.
+func (p *parser) newline() bool {
+	if p.consume("\n") || p.consume("\r\n") {
+		p.line++
+		return true
+	}
+	return false
+}
+
+// skipSpace moves the position to the next character
+// that is neither a space nor a tab.
+func (p *parser) skipSpace() {
+	for !p.eof() && (p.s[p.i] == ' ' || p.s[p.i] == '\t') {
+		p.i++
+	}
+}
+
+// skipComment moves the position to the end
+// of the line if there is a comment.
+func (p *parser) skipComment() {
+	if !p.eof() && p.s[p.i] == '#' {
+		p.i += len(p.rest())
+	}
+}
+
+// skipBlank skips spaces, comments, and new lines.
+func (p *parser) skipBlank() {
+	for {
+		p.skipSpace()
+		p.skipComment()
+		if !p.newline() {
+			return
+		}
+	}
+}
+
+// skipBlankNoComments skips spaces and new lines.
+func (p *parser) skipBlankNoComments() {
+	for {
+		if p.skipSpace(); !p.newline() {
+			return
+		}
+	}
+}
+
+// isBare returns true if the character is allowed in bare keys.
+func isBare(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// quoteKey returns elements of the key, the ones that
+// are not bare are quoted.
+func quoteKey(key []string) []string {
+	res := make([]string, len(key))
+	for i, k := range key {
+		res[i] = k
+		for j := 0; j < len(k); j++ {
+			if !isBare(k[j]) {
+				res[i] = strconv.Quote(k)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// normalize transforms arrays of tables into []interface{}.
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k := range t {
+			t[k] = normalize(t[k])
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = normalize(t[i])
+		}
+	case *tableArray:
+		lst := make([]interface{}, len(t.tables))
+		for i := range t.tables {
+			lst[i] = normalize(t.tables[i])
+		}
+		return lst
+	}
+	return v
+}
@@ -0,0 +1,97 @@
+package toml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type m = map[string]interface{}
+type l = []interface{}
+
+func TestParse(t *testing.T) {
+	for _, v := range []struct {
+		inp string
+		exp m
+	}{
+		{"", m{}},
+		{"# Comment only.\n\n", m{}},
+		{`key = "value" # Comment.`, m{"key": "value"}},
+		{`"quoted key" = 'C:\path'`, m{"quoted key": `C:\path`}},
+		{`s = "a\tb\u00e9\"c\\"`, m{"s": "a\tbé\"c\\"}},
+		{"s = \"\"\"\nline 1\nline 2\"\"\"", m{"s": "line 1\nline 2"}},
+		{"s = \"\"\"\none \\\n   two\"\"\"\"\"", m{"s": "one two\"\""}},
+		{"s = '''\nraw \\n\n'''", m{"s": "raw \\n\n"}},
+		{"i = 1_000\nn = -17\np = +5\nh = 0xff\no = 0o17\nb = 0b101", m{
+			"i": "1000", "n": "-17", "p": "5", "h": "255", "o": "15", "b": "5",
+		}},
+		{"f = 3.14\ne = -1e+5\ng = 6.626e-34\ni = inf\nn = -inf\nx = nan", m{
+			"f": "3.14", "e": "-1e+5", "g": "6.626e-34", "i": "inf", "n": "-inf", "x": "NaN",
+		}},
+		{"t = true\nf = false", m{"t": "true", "f": "false"}},
+		{"a = 1979-05-27T07:32:00Z\nb = 1979-05-27 00:32:00.999-07:00\nc = 1979-05-27\nd = 07:32:00", m{
+			"a": "1979-05-27T07:32:00Z", "b": "1979-05-27T00:32:00.999-07:00", "c": "1979-05-27", "d": "07:32:00",
+		}},
+		{"a = [1, 2, 3]\nb = [\n  'x',  # Comment.\n  \"y\",\n]\nc = [[1, 2], ['a']]\nd = []", m{
+			"a": l{"1", "2", "3"}, "b": l{"x", "y"}, "c": l{l{"1", "2"}, l{"a"}}, "d": l{},
+		}},
+		{"p = { x = 1, y.z = 'a' }\ne = {}", m{"p": m{"x": "1", "y": m{"z": "a"}}, "e": m{}}},
+		{"name = 'app'\n[db]\nuser = 'root'\n[db.primary]\nhost = 'localhost'\n[\"a b\".c]\nd = 1", m{
+			"name": "app",
+			"db":   m{"user": "root", "primary": m{"host": "localhost"}},
+			"a b":  m{"c": m{"d": "1"}},
+		}},
+		{"a.b.c = 1\na.b.d = 2\n[x.y]\n[x]\nz = 3", m{
+			"a": m{"b": m{"c": "1", "d": "2"}}, "x": m{"y": m{}, "z": "3"},
+		}},
+		{"[[users]]\nname = 'a'\n[users.info]\nage = 1\n[[users]]\nname = 'b'\n[users.info]\nage = 2", m{
+			"users": l{m{"name": "a", "info": m{"age": "1"}}, m{"name": "b", "info": m{"age": "2"}}},
+		}},
+		{"windows = 1\r\nok = true\r\n", m{"windows": "1", "ok": "true"}},
+	} {
+		res, _, err := parse(v.inp)
+		if err != nil {
+			t.Errorf("%q: No error expected, got %v.", v.inp, err)
+			continue
+		}
+		if !reflect.DeepEqual(res, v.exp) {
+			t.Errorf("%q: Expected %#v, got %#v.", v.inp, v.exp, res)
+		}
+	}
+}
+
+func TestParse_Incorrect(t *testing.T) {
+	for _, v := range []struct {
+		inp, err string
+	}{
+		{"key", `line 1: "=" expected after the key "key"`},
+		{"key =", "line 1: value expected"},
+		{"a = 1\na = 2", `line 2: duplicate key "a"`},
+		{"[a]\n[a]", `line 2: table "a" is already defined`},
+		{"a = 1\n[a]", `line 2: key "a" is already defined and is not a table`},
+		{"a = 1\n[[a]]", `line 2: key "a" is already defined and is not an array of tables`},
+		{"a = 1 b = 2", "line 1: end of line expected"},
+		{`a = "b`, "line 1: string is not terminated"},
+		{"a = \"b\nc\"", "line 1: basic string cannot contain new lines"},
+		{"a = 'b", "line 1: literal string is not terminated"},
+		{"\na = \"\"\"\nb", "line 3: multiline string is not terminated"},
+		{`a = "\q"`, "line 1: unknown escape sequence"},
+		{"a = [1, 2\nb = 3", `line 2: "," or "]" expected`},
+		{"a = [1,", "line 1: array is not terminated"},
+		{"a = {b = 1 c = 2}", `line 1: "," or "}" expected`},
+		{"a = 0123", `line 1: invalid number "0123"`},
+		{"a = 1__0", `line 1: invalid number "1__0"`},
+		{"a = .5", `line 1: invalid number ".5"`},
+		{"a = 99999999999999999999", `line 1: invalid number`},
+		{"a = 0xzz", `line 1: invalid number "0xzz"`},
+		{"a = yes", `line 1: invalid number "yes"`},
+		{"[a", `line 1: "]" expected`},
+		{"[[a]", `line 1: "]]" expected`},
+		{"= 1", "line 1: key expected"},
+	} {
+		_, _, err := parse(v.inp)
+		if err == nil || !strings.Contains(err.Error(), v.err) {
+			t.Errorf("%q: Error containing %q expected, got %v.", v.inp, v.err, err)
+		}
+	}
+}
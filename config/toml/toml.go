@@ -0,0 +1,50 @@
+// Package toml provides a type that implements Interface of the
+// "github.com/conveyer/config" for the TOML configuration format.
+package toml
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/goaltools/xflag/config/internal/tree"
+)
+
+// TOML is an implementation of config.Interface for toml
+// configuration files.
+// Tables are used as sections, i.e. a flag "database:port"
+// is looked up in the following document:
+//	[database]
+//	port = 28015
+// Dotted keys and nested tables are resolved the same way,
+// so "db:primary:host" may be defined as:
+//	[db.primary]
+//	host = "localhost"
+// Values of all types are returned as strings that are accepted by
+// the flags of the standard library and xflag/cflag package: integers
+// in decimal notation, floats and booleans as they are written, and
+// date-times in RFC 3339 format. Arrays of such values are returned
+// as []string by Strings method of the values, so they can be used
+// with slice flags.
+// Tables of joined files are merged recursively, values of
+// the later files have priority.
+type TOML struct {
+	*tree.Tree
+}
+
+// New allocates and returns a new TOML type.
+func New(data map[string]interface{}) *TOML {
+	return &TOML{tree.New(data, openFile)}
+}
+
+// openFile gets a path to TOML file, opens, parses, and returns it.
+func openFile(path string) (map[string]interface{}, tree.Lines, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, lines, err := parse(string(bs))
+	if err != nil {
+		return nil, nil, fmt.Errorf(`failed to parse "%s": %s`, path, err)
+	}
+	return m, lines, nil
+}
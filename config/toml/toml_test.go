@@ -0,0 +1,45 @@
+package toml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTOML(t *testing.T) {
+	c, err := New(nil).New("./testdata/file1.toml")
+	if err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.Join("./testdata/file2.toml"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+
+	for _, v := range []struct {
+		val, exp interface{}
+	}{
+		{c.Value("name").Interface(), "app"},
+		{c.Value("port").Interface(), "9090"},
+		{c.Value("hosts").Interface(), []string{"a.example.com", "b.example.com"}},
+		{c.Value("ratios").Interface(), []string{"0.5", "1e3"}},
+		{c.At("db").Value("debug").Interface(), "true"},
+		{c.At("db").Value("primary", "host").Interface(), "db.example.com"},
+		{c.At("db", "primary").Value("port").Interface(), "5432"},
+		{c.At("db", "primary").Value("started").Interface(), "1979-05-27T07:32:00Z"},
+		{c.Names("db"), []string{"debug", "primary"}},
+	} {
+		if !reflect.DeepEqual(v.val, v.exp) {
+			t.Errorf(`Expected "%v", got "%v".`, v.exp, v.val)
+		}
+	}
+}
+
+func TestTOML_IncorrectFile(t *testing.T) {
+	_, err := New(nil).New("./testdata/invalid.toml")
+	if err == nil || !strings.Contains(err.Error(), "invalid.toml") {
+		t.Errorf(`Error with the file name expected, got "%v".`, err)
+	}
+	if err := New(nil).Join("./testdata/doesNotExist.toml"); err == nil {
+		t.Errorf("Error expected, got nil.")
+	}
+}
@@ -0,0 +1,741 @@
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goaltools/xflag/config/internal/tree"
+)
+
+// line represents a single line of YAML document.
+type line struct {
+	num    int    // num is a number of the line starting from 1.
+	indent int    // indent is a number of leading spaces.
+	text   string // text is the content of the line without indentation.
+}
+
+// parser represents an instance of a single YAML parser.
+// It supports a subset of YAML that is enough for configuration
+// files: block and flow mappings and sequences, plain, quoted, and
+// block scalars, comments, and a single document per file.
+// Anchors, aliases, tags, and complex keys are not supported.
+type parser struct {
+	lines []line
+	pos   int
+
+	path []string
+	nums tree.Lines
+}
+
+// parse gets a YAML document, transforms it into a Go object and returns.
+// Mappings are represented as map[string]interface{}, sequences as
+// []interface{}, and scalars as strings. Null values are nil.
+// The root of the document is expected to be a mapping.
+// Numbers of the lines keys and items are defined on are
+// returned as a second argument.
+func parse(data []byte) (map[string]interface{}, tree.Lines, error) {
+	p := &parser{nums: tree.Lines{}}
+	if err := p.split(string(data)); err != nil {
+		return nil, nil, err
+	}
+
+	// Skip the optional document start marker.
+	p.skip()
+	if !p.eof() && isMarker(p.cur().text, "---") {
+		if rest := strings.TrimSpace(p.cur().text[3:]); rest != "" && !isComment(rest) {
+			return nil, nil, p.errorf("content after the document start marker is not supported")
+		}
+		p.pos++
+		p.skip()
+	}
+
+	// Empty documents are treated as empty mappings.
+	if p.eof() {
+		return map[string]interface{}{}, p.nums, nil
+	}
+	l := p.cur()
+	v, err := p.parseBlock(l.indent)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, nil, lineErrorf(l.num, "root of the document must be a mapping")
+	}
+
+	// Make sure the whole document has been processed.
+	p.skip()
+	if !p.eof() {
+		switch {
+		case isMarker(p.cur().text, "---"):
+			return nil, nil, p.errorf("multiple documents are not supported")
+		case !isMarker(p.cur().text, "..."):
+			return nil, nil, p.errorf("unexpected indentation")
+		}
+	}
+	return m, p.nums, nil
+}
+
+// split divides the input into lines and calculates their indentation.
+func (p *parser) split(s string) error {
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "\ufeff"), "\n")
+	for i, t := range strings.Split(s, "\n") {
+		t = strings.TrimRight(t, "\r")
+		text := strings.TrimLeft(t, " ")
+		if strings.HasPrefix(text, "\t") {
+			return lineErrorf(i+1, "tabs cannot be used for indentation")
+		}
+		p.lines = append(p.lines, line{num: i + 1, indent: len(t) - len(text), text: text})
+	}
+	return nil
+}
+
+// eof returns true if all of the lines have been processed.
+func (p *parser) eof() bool {
+	return p.pos >= len(p.lines)
+}
+
+// cur returns the current line.
+func (p *parser) cur() line {
+	return p.lines[p.pos]
+}
+
+// skip moves the position to the next line
+// that is neither empty nor a comment.
+func (p *parser) skip() {
+	for ; !p.eof(); p.pos++ {
+		if t := strings.TrimSpace(p.cur().text); t != "" && !isComment(t) {
+			return
+		}
+	}
+}
+
+// end returns true if there are no more lines
+// of the current document.
+func (p *parser) end() bool {
+	p.skip()
+	return p.eof() || p.cur().indent == 0 && (isMarker(p.cur().text, "---") || isMarker(p.cur().text, "..."))
+}
+
+// errorf returns an error associated with the current line.
+func (p *parser) errorf(format string, args ...interface{}) error {
+	num := len(p.lines)
+	if !p.eof() {
+		num = p.cur().num
+	}
+	return lineErrorf(num, format, args...)
+}
+
+// lineErrorf returns an error associated with the requested line.
+func lineErrorf(num int, format string, args ...interface{}) error {
+	return fmt.Errorf("yaml syntax error on line %d: %s", num, fmt.Sprintf(format, args...))
+}
+
+// parseBlock parses a node that starts at the current line
+// which has the requested indentation.
+func (p *parser) parseBlock(indent int) (interface{}, error) {
+	switch text := p.cur().text; true {
+	case isSeqItem(text):
+		return p.parseSeq(indent)
+	default:
+		if _, _, ok, err := splitKey(stripComment(text)); err != nil {
+			return nil, p.errorf("%s", err)
+		} else if ok {
+			return p.parseMap(indent)
+		}
+	}
+
+	// The node is neither a sequence nor a mapping,
+	// so it must be a scalar.
+	l := p.cur()
+	p.pos++
+	return p.parseInline(indent, l, l.text)
+}
+
+// parseMap parses a block mapping, every key of which
+// has the requested indentation.
+func (p *parser) parseMap(indent int) (interface{}, error) {
+	m := map[string]interface{}{}
+	for !p.end() {
+		l := p.cur()
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+
+		// Extract the key and make sure it is unique.
+		k, rest, ok, err := splitKey(stripComment(l.text))
+		switch {
+		case err != nil:
+			return nil, p.errorf("%s", err)
+		case !ok:
+			return nil, p.errorf(`mapping key is expected, got "%s"`, l.text)
+		}
+		if _, ok := m[k]; ok {
+			return nil, p.errorf(`duplicate key "%s"`, k)
+		}
+		p.pos++
+
+		// Parse the value of the key.
+		p.push(k, l.num)
+		if strings.TrimSpace(rest) == "" {
+			m[k], err = p.parseNested(indent, true)
+		} else {
+			m[k], err = p.parseInline(indent, l, rest)
+		}
+		p.pop()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// parseSeq parses a block sequence, every item of which
+// has the requested indentation.
+func (p *parser) parseSeq(indent int) (interface{}, error) {
+	lst := []interface{}{}
+	for !p.end() {
+		l := p.cur()
+		if l.indent < indent || l.indent == indent && !isSeqItem(l.text) {
+			break
+		}
+		if l.indent > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+
+		// Get the content of the item that follows the dash.
+		rest := strings.TrimLeft(l.text[1:], " ")
+		offset := len(l.text) - len(rest)
+
+		var v interface{}
+		var err error
+		p.push(strconv.Itoa(len(lst)), l.num)
+		switch _, _, ok, _ := splitKey(stripComment(rest)); true {
+		case strings.TrimSpace(stripComment(rest)) == "":
+			// The value of the item starts on the next line.
+			p.pos++
+			v, err = p.parseNested(indent, false)
+		case isSeqItem(rest) || ok:
+			// The item is a nested sequence or mapping that
+			// starts on the same line, e.g. "- key: value".
+			// Replace the current line by its content with
+			// a corresponding indentation and parse it as a block.
+			p.lines[p.pos] = line{num: l.num, indent: indent + offset, text: rest}
+			v, err = p.parseBlock(indent + offset)
+		default:
+			p.pos++
+			v, err = p.parseInline(indent, l, rest)
+		}
+		p.pop()
+		if err != nil {
+			return nil, err
+		}
+		lst = append(lst, v)
+	}
+	return lst, nil
+}
+
+// push adds the key to the path of the current node
+// and stores the number of the line it is defined on.
+func (p *parser) push(k string, num int) {
+	p.path = append(p.path, k)
+	p.nums[tree.Key(p.path)] = num
+}
+
+// pop removes the last element of the path of the current node.
+func (p *parser) pop() {
+	p.path = p.path[:len(p.path)-1]
+}
+
+// parseNested parses a value that starts on the line that follows
+// a mapping key or a sequence dash of the requested indentation.
+// Sequences of a mapping are allowed to have the same indentation
+// as their keys.
+func (p *parser) parseNested(indent int, inMap bool) (interface{}, error) {
+	if p.end() {
+		return nil, nil
+	}
+	switch l := p.cur(); true {
+	case l.indent > indent:
+		return p.parseBlock(l.indent)
+	case l.indent == indent && inMap && isSeqItem(l.text):
+		return p.parseSeq(indent)
+	}
+	return nil, nil
+}
+
+// parseInline parses a value s that is located on the line l after
+// a key or dash of the parent node of the requested indentation.
+// The current position is expected to point to the next line.
+func (p *parser) parseInline(indent int, l line, s string) (interface{}, error) {
+	switch s = strings.TrimSpace(s); true {
+	case strings.HasPrefix(s, "|") || strings.HasPrefix(s, ">"):
+		return p.parseBlockScalar(indent, l, s)
+	}
+
+	s = stripComment(s)
+	switch {
+	case strings.HasPrefix(s, "&") || strings.HasPrefix(s, "*"):
+		return nil, lineErrorf(l.num, "anchors and aliases are not supported")
+	case strings.HasPrefix(s, "!"):
+		return nil, lineErrorf(l.num, "tags are not supported")
+	case strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{"):
+		return p.parseFlowLines(l, s)
+	case !strings.HasPrefix(s, `"`) && !strings.HasPrefix(s, "'") && strings.Contains(s+" ", ": "):
+		// E.g. "a: b: c", the value must be quoted.
+		return nil, lineErrorf(l.num, "mapping values are not allowed in plain scalars")
+	}
+	v, err := parseScalar(s)
+	if err != nil {
+		return nil, lineErrorf(l.num, "%s", err)
+	}
+
+	// Multiline plain and quoted scalars are not supported.
+	if !p.end() && p.cur().indent > indent {
+		return nil, p.errorf(`multiline scalars are supported in block form only ("|" or ">")`)
+	}
+	return v, nil
+}
+
+// parseFlowLines parses a flow collection s that starts on the
+// line l and may span multiple lines.
+func (p *parser) parseFlowLines(l line, s string) (interface{}, error) {
+	for {
+		v, err := parseFlow(s)
+		if err != errUnterminated {
+			if err != nil {
+				return nil, lineErrorf(l.num, "%s", err)
+			}
+			return v, nil
+		}
+
+		// The collection is not closed, so append the next line.
+		if p.end() {
+			return nil, lineErrorf(l.num, "%s", err)
+		}
+		s += " " + stripComment(p.cur().text)
+		p.pos++
+	}
+}
+
+// parseBlockScalar parses a literal ("|") or folded (">") scalar
+// that is located on the lines that follow the line l.
+// The header of the scalar is expected as an input argument.
+func (p *parser) parseBlockScalar(indent int, l line, header string) (interface{}, error) {
+	// Parse the header: style, chomping and indentation indicators.
+	folded := header[0] == '>'
+	chomp, explicit := byte(0), 0
+	h := stripComment(header[1:])
+	for i := 0; i < len(h); i++ {
+		switch c := h[i]; true {
+		case (c == '-' || c == '+') && chomp == 0:
+			chomp = c
+		case c >= '1' && c <= '9' && explicit == 0:
+			explicit = int(c - '0')
+		default:
+			return nil, lineErrorf(l.num, `invalid block scalar header "%s"`, header)
+		}
+	}
+
+	// Collect the lines of the scalar. The indentation of the
+	// content is defined by its first non-empty line.
+	content := 0
+	if explicit > 0 {
+		content = indent + explicit
+	}
+	var ls []string
+	for ; !p.eof(); p.pos++ {
+		l := p.cur()
+		if strings.TrimSpace(l.text) == "" {
+			ls = append(ls, "")
+			continue
+		}
+		if content == 0 {
+			content = l.indent
+		}
+		if l.indent < content || l.indent <= indent {
+			break
+		}
+		ls = append(ls, strings.Repeat(" ", l.indent-content)+l.text)
+	}
+
+	// Separate trailing empty lines from the content.
+	n := len(ls)
+	for n > 0 && ls[n-1] == "" {
+		n--
+	}
+	trailing := len(ls) - n
+	ls = ls[:n]
+
+	// Join the lines.
+	var s string
+	switch folded {
+	case true:
+		for i := range ls {
+			// Line breaks between regular lines are folded into
+			// spaces, empty and more indented lines are preserved.
+			switch {
+			case i == 0 || ls[i-1] == "" && ls[i] != "":
+			case ls[i] == "" || strings.HasPrefix(ls[i], " ") || strings.HasPrefix(ls[i-1], " "):
+				s += "\n"
+			default:
+				s += " "
+			}
+			s += ls[i]
+		}
+	default:
+		s = strings.Join(ls, "\n")
+	}
+
+	// Process the trailing line breaks.
+	switch {
+	case n == 0:
+	case chomp == '-':
+	case chomp == '+':
+		s += strings.Repeat("\n", trailing+1)
+	default:
+		s += "\n"
+	}
+	return s, nil
+}
+
+// errUnterminated is returned by parseFlow if a flow collection is not closed.
+var errUnterminated = errors.New("flow collection is not terminated")
+
+// parseFlow parses a flow collection, i.e. "[a, b, c]" or "{a: b, c: d}".
+func parseFlow(s string) (interface{}, error) {
+	f := &flow{s: s}
+	v, err := f.value()
+	if err != nil {
+		return nil, err
+	}
+	if f.skip(); f.i < len(f.s) {
+		return nil, fmt.Errorf(`unexpected characters "%s" after the flow collection`, f.s[f.i:])
+	}
+	return v, nil
+}
+
+// flow represents a state of the flow collection parser.
+type flow struct {
+	s string
+	i int
+}
+
+// skip moves the position to the next non-space character.
+func (f *flow) skip() {
+	for f.i < len(f.s) && f.s[f.i] == ' ' {
+		f.i++
+	}
+}
+
+// value parses a collection or a scalar that starts at the current position.
+func (f *flow) value() (interface{}, error) {
+	f.skip()
+	if f.i >= len(f.s) {
+		return nil, errUnterminated
+	}
+	switch f.s[f.i] {
+	case '[':
+		return f.seq()
+	case '{':
+		return f.mapping()
+	}
+	return f.scalar(false)
+}
+
+// seq parses a flow sequence.
+func (f *flow) seq() (interface{}, error) {
+	lst := []interface{}{}
+	for f.i++; ; {
+		if f.skip(); f.i >= len(f.s) {
+			return nil, errUnterminated
+		}
+		if f.s[f.i] == ']' {
+			f.i++
+			return lst, nil
+		}
+		v, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		lst = append(lst, v)
+		if err := f.separator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// mapping parses a flow mapping.
+func (f *flow) mapping() (interface{}, error) {
+	m := map[string]interface{}{}
+	for f.i++; ; {
+		if f.skip(); f.i >= len(f.s) {
+			return nil, errUnterminated
+		}
+		if f.s[f.i] == '}' {
+			f.i++
+			return m, nil
+		}
+
+		// Parse the key.
+		k, err := f.scalar(true)
+		if err != nil {
+			return nil, err
+		}
+		ks, ok := k.(string)
+		if !ok {
+			return nil, errors.New("null keys are not supported")
+		}
+		if _, ok := m[ks]; ok {
+			return nil, fmt.Errorf(`duplicate key "%s"`, ks)
+		}
+
+		// Parse the value, if any.
+		var v interface{}
+		if f.skip(); f.i < len(f.s) && f.s[f.i] == ':' {
+			f.i++
+			if v, err = f.value(); err != nil {
+				return nil, err
+			}
+		}
+		m[ks] = v
+		if err := f.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator makes sure the current element of a collection
+// is followed by a comma or the end of the collection.
+func (f *flow) separator(end byte) error {
+	switch f.skip(); true {
+	case f.i >= len(f.s):
+		return errUnterminated
+	case f.s[f.i] == ',':
+		f.i++
+	case f.s[f.i] != end:
+		return fmt.Errorf(`"," or "%c" expected near "%s"`, end, f.s[f.i:])
+	}
+	return nil
+}
+
+// scalar parses a quoted or plain scalar of a flow collection.
+// Plain keys of mappings are terminated by a colon.
+func (f *flow) scalar(key bool) (interface{}, error) {
+	if c := f.s[f.i]; c == '"' || c == '\'' {
+		end := quoteEnd(f.s[f.i:])
+		if end < 0 {
+			return nil, errUnterminated
+		}
+		v, err := parseScalar(f.s[f.i : f.i+end+1])
+		f.i += end + 1
+		return v, err
+	}
+	beg := f.i
+	for ; f.i < len(f.s); f.i++ {
+		c := f.s[f.i]
+		if c == ',' || c == ']' || c == '}' || c == '[' || c == '{' {
+			break
+		}
+		if c == ':' && (key || f.i+1 == len(f.s) || f.s[f.i+1] == ' ' || f.s[f.i+1] == ',') {
+			break
+		}
+	}
+	return parseScalar(f.s[beg:f.i])
+}
+
+// parseScalar parses a plain or quoted scalar.
+// Nil is returned for null values.
+func parseScalar(s string) (interface{}, error) {
+	switch s = strings.TrimSpace(s); s {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	}
+	switch s[0] {
+	case '"':
+		if quoteEnd(s) != len(s)-1 {
+			return nil, fmt.Errorf("string literal of `%s` not terminated", s)
+		}
+		return unescape(s[1 : len(s)-1])
+	case '\'':
+		if quoteEnd(s) != len(s)-1 {
+			return nil, fmt.Errorf("string literal of `%s` not terminated", s)
+		}
+		return strings.Replace(s[1:len(s)-1], "''", "'", -1), nil
+	case '@', '`', '%':
+		return nil, fmt.Errorf(`plain scalar cannot start with "%c"`, s[0])
+	}
+	return s, nil
+}
+
+// quoteEnd returns an index of the quote that terminates
+// the quoted scalar at the beginning of s or -1 if there is no such quote.
+func quoteEnd(s string) int {
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case q == '"' && s[i] == '\\':
+			i++
+		case q == '\'' && s[i] == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == q:
+			return i
+		}
+	}
+	return -1
+}
+
+// unescape processes escape sequences of a double-quoted scalar.
+func unescape(s string) (string, error) {
+	var res []byte
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			res = append(res, s[i])
+			continue
+		}
+		if i++; i == len(s) {
+			return "", errors.New("incomplete escape sequence")
+		}
+		size := 0
+		switch s[i] {
+		case '0':
+			res = append(res, 0)
+		case 'a':
+			res = append(res, '\a')
+		case 'b':
+			res = append(res, '\b')
+		case 't', '\t':
+			res = append(res, '\t')
+		case 'n':
+			res = append(res, '\n')
+		case 'v':
+			res = append(res, '\v')
+		case 'f':
+			res = append(res, '\f')
+		case 'r':
+			res = append(res, '\r')
+		case 'e':
+			res = append(res, 0x1b)
+		case ' ', '"', '/', '\\':
+			res = append(res, s[i])
+		case 'x':
+			size = 2
+		case 'u':
+			size = 4
+		case 'U':
+			size = 8
+		default:
+			return "", fmt.Errorf(`unknown escape sequence "\%c"`, s[i])
+		}
+		if size == 0 {
+			continue
+		}
+		if i+size >= len(s) {
+			return "", errors.New("incomplete escape sequence")
+		}
+		r, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+		if err != nil {
+			return "", fmt.Errorf(`invalid escape sequence "\%s"`, s[i:i+1+size])
+		}
+		var buf [utf8.UTFMax]byte
+		res = append(res, buf[:utf8.EncodeRune(buf[:], rune(r))]...)
+		i += size
+	}
+	return string(res), nil
+}
+
+// splitKey extracts a key of the mapping entry and
+// returns it along with the rest of the line.
+// False is returned if the line is not a mapping entry.
+func splitKey(s string) (key, rest string, ok bool, err error) {
+	if s == "" {
+		return "", "", false, nil
+	}
+
+	// Quoted keys.
+	if s[0] == '"' || s[0] == '\'' {
+		end := quoteEnd(s)
+		if end < 0 {
+			return "", "", false, nil
+		}
+		t := strings.TrimLeft(s[end+1:], " ")
+		if !isIndicator(t) {
+			return "", "", false, nil
+		}
+		k, err := parseScalar(s[:end+1])
+		if err != nil {
+			return "", "", false, err
+		}
+		return k.(string), t[1:], true, nil
+	}
+
+	// Flow collections cannot be keys.
+	if s[0] == '[' || s[0] == '{' {
+		return "", "", false, nil
+	}
+	if s[0] == '?' && (len(s) == 1 || s[1] == ' ') {
+		return "", "", false, errors.New("complex keys are not supported")
+	}
+
+	// Plain keys.
+	for i := range s {
+		if isIndicator(s[i:]) {
+			k := strings.TrimSpace(s[:i])
+			if k == "" {
+				return "", "", false, errors.New("empty keys are not supported")
+			}
+			return k, s[i+1:], true, nil
+		}
+	}
+	return "", "", false, nil
+}
+
+// isIndicator returns true if s starts with a mapping
+// value indicator, i.e. a colon followed by a space.
+func isIndicator(s string) bool {
+	return strings.HasPrefix(s, ":") && (len(s) == 1 || s[1] == ' ')
+}
+
+// isSeqItem returns true if the text is an item of a block sequence.
+func isSeqItem(s string) bool {
+	return s == "-" || strings.HasPrefix(s, "- ")
+}
+
+// isComment returns true if the text is a comment.
+func isComment(s string) bool {
+	return strings.HasPrefix(s, "#")
+}
+
+// isMarker returns true if the text is a document marker
+// such as "---" or "...".
+func isMarker(s, m string) bool {
+	return s == m || strings.HasPrefix(s, m+" ")
+}
+
+// stripComment removes a trailing comment of the text.
+// A comment is started by a "#" character that is not quoted and
+// either located at the beginning of the line or preceded by a space.
+func stripComment(s string) string {
+	var q byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; true {
+		case q == '"' && c == '\\':
+			i++
+		case q != 0:
+			if c == q {
+				q = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" [{,:", s[i-1]) >= 0):
+			q = c
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return strings.TrimRight(s[:i], " ")
+		}
+	}
+	return strings.TrimRight(s, " ")
+}
@@ -0,0 +1,88 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type m = map[string]interface{}
+type l = []interface{}
+
+func TestParse(t *testing.T) {
+	for _, v := range []struct {
+		inp string
+		exp m
+	}{
+		{"", m{}},
+		{"# Comment only.\n---\n", m{}},
+		{"key: value", m{"key": "value"}},
+		{"key:   value  # Comment.\nkey2: 'quoted # value'  # Comment.", m{"key": "value", "key2": "quoted # value"}},
+		{"url: http://example.com:8080/#anchor", m{"url": "http://example.com:8080/#anchor"}},
+		{"a: ~\nb: null\nc:\nd: ''", m{"a": nil, "b": nil, "c": nil, "d": ""}},
+		{`"quoted key": "a\tb\u00e9\"c"`, m{"quoted key": "a\tbé\"c"}},
+		{"'it''s': 'don''t'", m{"it's": "don't"}},
+		{"a: 'b: c'\nd: \"e: f\"", m{"a": "b: c", "d": "e: f"}},
+		{"\n---\ndb:\n  primary:\n    host: localhost\n    port: 5432\n  user: root\nname: app\n", m{
+			"db":   m{"primary": m{"host": "localhost", "port": "5432"}, "user": "root"},
+			"name": "app",
+		}},
+		{"list:\n  - a\n  - b\n\n  # Comment.\n  - c\nother: x", m{"list": l{"a", "b", "c"}, "other": "x"}},
+		{"list:\n- a\n- b\nother: x", m{"list": l{"a", "b"}, "other": "x"}},
+		{"list: [a, 'b, c', \"d\", 1.5]", m{"list": l{"a", "b, c", "d", "1.5"}}},
+		{"list: [\n  a,\n  b,  # Comment.\n]", m{"list": l{"a", "b"}}},
+		{"obj: {a: 1, b: [x, y], c: {d: e}, f: http://x.y}", m{"obj": m{"a": "1", "b": l{"x", "y"}, "c": m{"d": "e"}, "f": "http://x.y"}}},
+		{"empty: []\nempty2: {}", m{"empty": l{}, "empty2": m{}}},
+		{"users:\n  - name: a\n    age: 1\n  - name: b\n  -\n    - c\n    - d\n  - - e", m{
+			"users": l{m{"name": "a", "age": "1"}, m{"name": "b"}, l{"c", "d"}, l{"e"}},
+		}},
+		{"text: |\n  line 1\n    line 2\n\n  line 3\n\nnext: x", m{"text": "line 1\n  line 2\n\nline 3\n", "next": "x"}},
+		{"text: |-\n  a\n  b\n", m{"text": "a\nb"}},
+		{"text: |+\n  a\n\n\n", m{"text": "a\n\n\n"}},
+		{"text: >\n  a\n  b\n\n  c\n", m{"text": "a b\nc\n"}},
+		{"list:\n  - |\n    a\n    b\n  - c", m{"list": l{"a\nb\n", "c"}}},
+		{"key: value\n...\nignored", m{"key": "value"}},
+		{"windows: value\r\nok: true\r\n", m{"windows": "value", "ok": "true"}},
+	} {
+		res, _, err := parse([]byte(v.inp))
+		if err != nil {
+			t.Errorf("%q: No error expected, got %v.", v.inp, err)
+			continue
+		}
+		if !reflect.DeepEqual(res, v.exp) {
+			t.Errorf("%q: Expected %#v, got %#v.", v.inp, v.exp, res)
+		}
+	}
+}
+
+func TestParse_Incorrect(t *testing.T) {
+	for _, v := range []struct {
+		inp, err string
+	}{
+		{"- a\n- b", "line 1: root of the document must be a mapping"},
+		{"scalar", "line 1: root of the document must be a mapping"},
+		{"a: b\n\tc: d", "line 2: tabs cannot be used"},
+		{"a: b\n  c: d", "line 2: multiline scalars"},
+		{"a:\n  b: c\n d: e", "line 3: unexpected indentation"},
+		{"a: b\na: c", "line 2: duplicate key"},
+		{"a: b\njust text", "line 2: mapping key is expected"},
+		{"a: b: c", "line 1: mapping values are not allowed"},
+		{"a:\n- b: c: d", "line 2: mapping values are not allowed"},
+		{"a: b:", "line 1: mapping values are not allowed"},
+		{"a: &anchor b", "line 1: anchors and aliases"},
+		{"a: !!str b", "line 1: tags are not supported"},
+		{"a: [b, c\n", "line 1: flow collection is not terminated"},
+		{"a: [b c] d", "line 1: unexpected characters"},
+		{"a: {b: c d: e}", `line 1: "," or "}" expected`},
+		{`a: "b`, "line 1: string literal"},
+		{`a: "\q"`, "line 1: unknown escape sequence"},
+		{"a: |x\n  b", "line 1: invalid block scalar header"},
+		{"? a\n: b", "line 1: complex keys are not supported"},
+		{"a: b\n---\nc: d", "line 2: multiple documents are not supported"},
+	} {
+		_, _, err := parse([]byte(v.inp))
+		if err == nil || !strings.Contains(err.Error(), v.err) {
+			t.Errorf("%q: Error containing %q expected, got %v.", v.inp, v.err, err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Package yaml provides a type that implements Interface of the
+// "github.com/conveyer/config" for the YAML configuration format.
+// A subset of YAML that is enough for configuration files is
+// supported: block and flow mappings and sequences, plain,
+// quoted, and block scalars, and comments. Anchors, aliases, tags,
+// and multiple documents per file are not.
+package yaml
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/goaltools/xflag/config/internal/tree"
+)
+
+// YAML is an implementation of config.Interface for yaml
+// configuration files.
+// Mappings are used as sections, a path of any depth is resolved
+// through nested mappings, i.e. a flag "db:primary:host" is
+// looked up in the following document:
+//	db:
+//	  primary:
+//	    host: localhost
+// Scalars are returned as they are written in the file. Sequences of
+// scalars are returned as []string by Strings method of the values,
+// so they can be used with slice flags of xflag/cflag package.
+// Mappings of joined files are merged recursively, values of
+// the later files have priority.
+type YAML struct {
+	*tree.Tree
+}
+
+// New allocates and returns a new YAML type.
+func New(data map[string]interface{}) *YAML {
+	return &YAML{tree.New(data, openFile)}
+}
+
+// openFile gets a path to YAML file, opens, parses, and returns it.
+func openFile(path string) (map[string]interface{}, tree.Lines, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, lines, err := parse(bs)
+	if err != nil {
+		return nil, nil, fmt.Errorf(`failed to parse "%s": %s`, path, err)
+	}
+	return m, lines, nil
+}
@@ -0,0 +1,44 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestYAML(t *testing.T) {
+	c, err := New(nil).New("./testdata/file1.yml")
+	if err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.Join("./testdata/file2.yml"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+
+	for _, v := range []struct {
+		val, exp interface{}
+	}{
+		{c.Value("name").Interface(), "app"},
+		{c.Value("port").Interface(), "9090"},
+		{c.Value("hosts").Interface(), []string{"a.example.com", "b.example.com"}},
+		{c.At("db").Value("primary", "host").Interface(), "db.example.com"},
+		{c.At("db").Value("primary", "port").Interface(), "5432"},
+		{c.At("db", "primary").Value("port").Interface(), "5432"},
+		{c.At("db").Value("replicas").Interface(), []string{"r1", "r2"}},
+		{c.Names("db"), []string{"primary", "replicas"}},
+	} {
+		if !reflect.DeepEqual(v.val, v.exp) {
+			t.Errorf(`Expected "%v", got "%v".`, v.exp, v.val)
+		}
+	}
+}
+
+func TestYAML_IncorrectFile(t *testing.T) {
+	_, err := New(nil).New("./testdata/invalid.yml")
+	if err == nil || !strings.Contains(err.Error(), "invalid.yml") {
+		t.Errorf(`Error with the file name expected, got "%v".`, err)
+	}
+	if err := New(nil).Join("./testdata/doesNotExist.yml"); err == nil {
+		t.Errorf("Error expected, got nil.")
+	}
+}
@@ -0,0 +1,77 @@
+package xflag
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/conveyer/config"
+)
+
+// Dir parses configuration files of the directory, e.g. "conf.d",
+// in lexical order of their names as if they were passed to the Files
+// method, so "50-local.ini" overrides values of "10-db.ini".
+// Only files with the specified extensions (e.g. ".ini") are parsed,
+// all extensions of the Formats are used if none are specified.
+// Every file is parsed by the configuration of the Formats associated
+// with its extension. Subdirectories and hidden files are ignored.
+// An error is returned if the directory cannot be read, some of the
+// extensions are not supported, or some of the files cannot be parsed.
+func (c *Context) Dir(path string, exts ...string) error {
+	// Collect the requested formats.
+	formats := c.Formats
+	if len(exts) > 0 {
+		formats = map[string]config.Interface{}
+		for _, ext := range exts {
+			conf, ok := c.Formats[strings.ToLower(ext)]
+			if !ok {
+				return fmt.Errorf(`unsupported extension "%s" of configuration files`, ext)
+			}
+			formats[strings.ToLower(ext)] = conf
+		}
+	}
+
+	// Parse the files that match the formats.
+	d := &dir{path: path, formats: formats, at: len(c.files)}
+	files, err := d.list()
+	if err != nil {
+		return err
+	}
+	d.n = len(files)
+	c.files = append(c.files, files...)
+	c.dirs = append(c.dirs, d)
+	return nil
+}
+
+// dir represents a directory that was passed to the Dir method,
+// so Watcher can pick up its new files. Files of the directory
+// are located in the files of the Context starting at the index
+// at, n is their number.
+type dir struct {
+	path    string
+	formats map[string]config.Interface
+	at, n   int
+}
+
+// list parses the files of the directory that match the formats.
+func (d *dir) list() (files []file, err error) {
+	fis, err := ioutil.ReadDir(d.path)
+	if err != nil {
+		return nil, err
+	}
+	for _, fi := range fis {
+		n := fi.Name()
+		conf, ok := d.formats[strings.ToLower(filepath.Ext(n))]
+		if !ok || fi.IsDir() || strings.HasPrefix(n, ".") {
+			continue
+		}
+		name := filepath.Join(d.path, n)
+		f, err := conf.New(name)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file{name: name, conf: f})
+	}
+	return
+}
@@ -0,0 +1,45 @@
+package xflag
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+func TestContext_Dir(t *testing.T) {
+	for _, v := range []struct {
+		exts []string
+		exp  []string
+	}{
+		{nil, []string{"local", "localhost", "5433"}},
+		{[]string{".INI"}, []string{"local", "localhost", "5432"}},
+		{[]string{".json"}, []string{"json", "", "5433"}},
+	} {
+		fset := flag.NewFlagSet("test", flag.ContinueOnError)
+		name := fset.String("name", "", "")
+		host := fset.String("database:host", "", "")
+		port := fset.String("database:port", "", "")
+
+		c := New(ini.New(nil), nil)
+		if err := c.Dir("./testdata/conf.d", v.exts...); err != nil {
+			t.Fatalf(`No error expected, got "%v".`, err)
+		}
+		if err := c.ParseSet(fset); err != nil {
+			t.Fatalf(`No error expected, got "%v".`, err)
+		}
+		for i, r := range []string{*name, *host, *port} {
+			if r != v.exp[i] {
+				t.Errorf(`Extensions %v: expected "%s", got "%s".`, v.exts, v.exp[i], r)
+			}
+		}
+	}
+
+	c := New(ini.New(nil), nil)
+	if err := c.Dir("./testdata/conf.d", ".xml"); err == nil {
+		t.Errorf("Unsupported extension: error expected.")
+	}
+	if err := c.Dir("./testdata/doesNotExist"); err == nil {
+		t.Errorf("Directory does not exist: error expected.")
+	}
+}
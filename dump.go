@@ -0,0 +1,137 @@
+package xflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goaltools/xflag/cflag/types"
+)
+
+// Redacted is a value that is written instead of values
+// of secret flags if redaction is requested.
+const Redacted = "REDACTED"
+
+// WriteINI writes current values of all the flags of the flag set
+// to the w as an INI configuration file. It is supposed to be
+// called after ParseSet so the result contains the effective
+// configuration: defaults, files, environment variables, and
+// command line arguments combined.
+// If redact is true, values of the flags marked as secret
+// are replaced by Redacted.
+// The output is written in the same format as by WriteSample
+// and is parsed by the INI parser back into the same values.
+func (c *Context) WriteINI(w io.Writer, fset *flag.FlagSet, redact bool) error {
+	var buf bytes.Buffer
+	err := c.writeINI(&buf, fset, func(f *flag.Flag, arr bool) []string {
+		return c.current(f, arr, redact)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// WriteJSON writes current values of all the flags of the flag set
+// to the w as a JSON document. Flag names are split into paths of
+// nested objects using the Separator, values of slice flags are
+// represented as arrays, and values of map flags as objects.
+// All values are written as strings.
+// If redact is true, values of the flags marked as secret
+// are replaced by Redacted.
+// Example of the output:
+//	{
+//		"database": {
+//			"hosts": ["localhost"],
+//			"port": "5432"
+//		}
+//	}
+func (c *Context) WriteJSON(w io.Writer, fset *flag.FlagSet, redact bool) (err error) {
+	root := map[string]interface{}{}
+	fset.VisitAll(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+
+		// Find the object the value of the flag belongs to.
+		path, arr := c.flagPath(f.Name)
+		obj := root
+		for _, k := range path[:len(path)-1] {
+			if obj[k] == nil {
+				obj[k] = map[string]interface{}{}
+			}
+			m, ok := obj[k].(map[string]interface{})
+			if !ok {
+				err = fmt.Errorf(`flag "%s" conflicts with a value of "%s"`, f.Name, k)
+				return
+			}
+			obj = m
+		}
+
+		// Add the value to the object.
+		k := path[len(path)-1]
+		if _, ok := obj[k]; ok {
+			err = fmt.Errorf(`flag "%s" conflicts with another flag`, f.Name)
+			return
+		}
+		vs := c.current(f, arr, redact)
+		if c.isMap(f.Name) && !(redact && c.marked(f.Name, secret)) {
+			// Maps are represented as objects.
+			m := map[string]interface{}{}
+			for _, v := range vs {
+				if i := strings.Index(v, "="); i >= 0 {
+					m[v[:i]] = v[i+1:]
+				}
+			}
+			obj[k] = m
+			return
+		}
+		if arr {
+			if vs == nil {
+				vs = []string{}
+			}
+			obj[k] = vs
+			return
+		}
+		obj[k] = vs[0]
+	})
+	if err != nil {
+		return err
+	}
+
+	bs, err := json.MarshalIndent(root, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(bs, '\n'))
+	return err
+}
+
+// current returns the current value of the flag. If arr is true,
+// the value is returned as a list of its elements.
+// Values of secret flags are redacted if requested.
+func (c *Context) current(f *flag.Flag, arr, redact bool) []string {
+	if redact && c.marked(f.Name, secret) {
+		return []string{Redacted}
+	}
+	if !arr {
+		return []string{f.Value.String()}
+	}
+	return elements(f.Value)
+}
+
+// elements returns elements of the slice flag's value.
+// Values of unknown types are split using types.Split.
+func elements(v flag.Value) []string {
+	if s, ok := v.(*slice); ok {
+		v = s.Value
+	}
+	if ss, ok := types.Elements(v); ok {
+		return ss
+	}
+	return types.Split(v.String())
+}
@@ -0,0 +1,141 @@
+package xflag
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+var (
+	dumpFlags = flagDefs{
+		{"name", "", ""},
+		{"note", "", ""},
+		{"database:password", "", ""},
+		{"database:hosts[]", []string(nil), ""},
+		{"ports[]", []int(nil), ""},
+	}
+	dumpArgs = []string{"--name", "  James  ", "--note", `say "hi"`, "--database:password", "secret"}
+)
+
+func TestContext_WriteINI_WriteJSON(t *testing.T) {
+	for _, v := range []struct {
+		write func(*Context, io.Writer, *flag.FlagSet, bool) error
+		exp   string
+	}{
+		{
+			(*Context).WriteINI,
+			`name = "  James  "
+
+note = say "hi"
+
+# ports[] =
+
+[database]
+hosts[] = localhost
+hosts[] = "#1; #2"
+
+password = REDACTED
+`,
+		},
+		{
+			(*Context).WriteJSON,
+			`{
+	"database": {
+		"hosts": [
+			"localhost",
+			"#1; #2"
+		],
+		"password": "REDACTED"
+	},
+	"name": "  James  ",
+	"note": "say \"hi\"",
+	"ports": []
+}
+`,
+		},
+	} {
+		fset := dumpFlags.flagSet()
+		c := New(ini.New(nil), dumpArgs)
+		c.Secret("database:password")
+		if err := c.Files("./testdata/dump.ini"); err != nil {
+			t.Fatalf(`No error expected, got "%v".`, err)
+		}
+		if err := c.ParseSet(fset); err != nil {
+			t.Fatalf(`No error expected, got "%v".`, err)
+		}
+
+		var buf bytes.Buffer
+		if err := v.write(c, &buf, fset, true); err != nil {
+			t.Fatalf(`No error expected, got "%v".`, err)
+		}
+		if res := buf.String(); res != v.exp {
+			t.Errorf(`Expected "%s", got "%s".`, v.exp, res)
+		}
+	}
+}
+
+func TestContext_WriteINI_RoundTrip(t *testing.T) {
+	for _, v := range []string{
+		"", "${HOME}", "$${HOME}", "${HOME:-none}", "${server:host}", "$${server:host}", "$$${HOME}",
+	} {
+		fset := dumpFlags.flagSet()
+		c := New(ini.New(nil), dumpArgs)
+		if err := c.Files("./testdata/dump.ini"); err != nil {
+			t.Fatalf(`No error expected, got "%v".`, err)
+		}
+		if err := c.ParseSet(fset); err != nil {
+			t.Fatalf(`No error expected, got "%v".`, err)
+		}
+		if v != "" {
+			fset.Set("note", v)
+			fset.Set("database:hosts[]", v)
+		}
+
+		var buf bytes.Buffer
+		if err := c.WriteINI(&buf, fset, false); err != nil {
+			t.Fatalf(`No error expected, got "%v".`, err)
+		}
+		f, err := ioutil.TempFile("", "xflag")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(f.Name())
+		buf.WriteTo(f)
+		f.Close()
+
+		res := dumpFlags.flagSet()
+		c = New(ini.New(nil), nil)
+		if err := c.Files(f.Name()); err != nil {
+			t.Fatalf(`No error expected, got "%v".`, err)
+		}
+		if err := c.ParseSet(res); err != nil {
+			t.Fatalf(`No error expected, got "%v".`, err)
+		}
+		fset.VisitAll(func(f *flag.Flag) {
+			if r, exp := res.Lookup(f.Name).Value.String(), f.Value.String(); r != exp {
+				t.Errorf(`"%s": "%s": Expected "%s", got "%s".`, v, f.Name, exp, r)
+			}
+		})
+	}
+}
+
+func TestContext_WriteINI_Unquotable(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.String("name", ` "quoted" `, "")
+	if err := New(ini.New(nil), nil).WriteINI(&bytes.Buffer{}, fset, false); err == nil {
+		t.Errorf("Error expected, got nil.")
+	}
+}
+
+func TestContext_WriteJSON_Conflict(t *testing.T) {
+	fset := dumpFlags.flagSet()
+	fset.String("name:first", "", "")
+	if err := New(ini.New(nil), nil).WriteJSON(&bytes.Buffer{}, fset, true); err == nil {
+		t.Errorf("Error expected, got nil.")
+	}
+}
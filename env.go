@@ -0,0 +1,57 @@
+package xflag
+
+import (
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Env method makes the ParseSet look up every flag in environment
+// variables. Their values have priority over the configuration
+// files but not over the command line arguments.
+// Names of the variables are returned by the EnvMapper, by default
+// a flag "database:port" is looked up in the "PREFIX_DATABASE_PORT"
+// variable. Values of slice flags are split using the EnvDelimiter.
+func (c *Context) Env(prefix string) {
+	c.env = true
+	c.envPrefix = prefix
+}
+
+// EnvName is the default EnvMapper of the Context. It joins the prefix
+// and elements of the flag path using "_" as a separator, turns
+// the result into upper case, and replaces all characters that are
+// neither letters nor digits by underscores. E.g. a prefix "app"
+// and a path ["database", "port"] produce "APP_DATABASE_PORT".
+func EnvName(prefix string, path []string) string {
+	if prefix != "" {
+		path = append([]string{prefix}, path...)
+	}
+	return strings.Map(func(r rune) rune {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return '_'
+		}
+		return unicode.ToUpper(r)
+	}, strings.Join(path, "_"))
+}
+
+// lookupEnv receives a value of the environment variable associated
+// with the path. Values of arrays are split by the EnvDelimiter,
+// empty variables are ignored in this case.
+// False is returned if the Env method hasn't been called
+// or there is no such variable.
+func (c *Context) lookupEnv(path []string, arr bool) (string, []string, bool) {
+	if !c.env {
+		return "", nil, false
+	}
+	name := c.EnvMapper(c.envPrefix, path)
+	v, ok := os.LookupEnv(name)
+	switch {
+	case !ok:
+		return name, nil, false
+	case !arr:
+		return name, []string{v}, true
+	case v == "":
+		return name, nil, false
+	}
+	return name, strings.Split(v, c.EnvDelimiter), true
+}
@@ -0,0 +1,161 @@
+package xflag
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/goaltools/xflag/cflag/types"
+	"github.com/goaltools/xflag/config/json"
+
+	"github.com/conveyer/config"
+	"github.com/goaltools/xflag/config/ini"
+)
+
+func TestEnvName(t *testing.T) {
+	for _, v := range []struct {
+		prefix string
+		path   []string
+		exp    string
+	}{
+		{"", []string{"port"}, "PORT"},
+		{"app", []string{"database", "port"}, "APP_DATABASE_PORT"},
+		{"my-app", []string{"some.section", "my-key"}, "MY_APP_SOME_SECTION_MY_KEY"},
+	} {
+		if res := EnvName(v.prefix, v.path); res != v.exp {
+			t.Errorf(`Expected "%s", got "%s".`, v.exp, res)
+		}
+	}
+}
+
+func TestParseSet_Env(t *testing.T) {
+	for k, v := range map[string]string{
+		"XFLAG_TEST_KEY1":         "env_value1",
+		"XFLAG_TEST_SECTION_KEY1": "env_value2",
+		"XFLAG_TEST_KEY2":         "a,b",
+		"XFLAG_TEST_EMPTY":        "",
+		"XFLAG_TEST_ARG":          "env_arg",
+		"XFLAG_TEST_AGE":          "abc",
+	} {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	key1 := fset.String("key1", "", "")
+	sectKey1 := fset.String("section:key1", "", "")
+	key2 := &types.Strings{}
+	fset.Var(key2, "key2[]", "")
+	empty := &types.Strings{Value: []string{"default"}}
+	fset.Var(empty, "empty[]", "")
+	arg := fset.String("arg", "", "")
+	fset.Int("age", 0, "")
+
+	c := New(ini.New(nil), []string{"--arg", "value"})
+	c.Env("xflag_test")
+	if err := c.Files("./testdata/file1.ini", "./testdata/file2.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	err := c.ParseSet(fset)
+	if errs, ok := err.(Errors); !ok || len(errs) != 1 || errs[0].(*SetError).Env != "XFLAG_TEST_AGE" {
+		t.Errorf(`A single error of environment variable expected, got "%v".`, err)
+	}
+
+	for _, v := range []struct {
+		val, exp interface{}
+	}{
+		{*key1, "env_value1"},
+		{*sectKey1, "env_value2"},
+		{key2.Value, []string{"a", "b"}},
+		{empty.Value, []string{"default"}},
+		{*arg, "value"},
+	} {
+		if !reflect.DeepEqual(v.val, v.exp) {
+			t.Errorf(`Incorrect value of the flag. Expected "%v", got "%v".`, v.exp, v.val)
+		}
+	}
+}
+
+func TestParseSet_EnvMapper(t *testing.T) {
+	os.Setenv("custom.key1", "a;b")
+	defer os.Unsetenv("custom.key1")
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	key1 := &types.Strings{}
+	fset.Var(key1, "key1[]", "")
+
+	c := New(ini.New(nil), nil)
+	c.Env("custom")
+	c.EnvDelimiter = ";"
+	c.EnvMapper = func(prefix string, path []string) string {
+		return prefix + "." + path[0]
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Errorf(`No error expected, got "%v".`, err)
+	}
+	if exp := []string{"a", "b"}; !reflect.DeepEqual(key1.Value, exp) {
+		t.Errorf(`Expected "%v", got "%v".`, exp, key1.Value)
+	}
+}
+
+func TestContext_Files_EnvExpansion(t *testing.T) {
+	os.Setenv("XFLAG_TEST_PORT", "9090")
+	defer os.Unsetenv("XFLAG_TEST_PORT")
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	host := fset.String("host", "", "")
+	port := fset.String("port", "", "")
+	addr := fset.String("addr", "", "")
+	tls := fset.String("tls", "default", "")
+	literal := fset.String("literal", "", "")
+
+	c := New(ini.New(nil), nil)
+	if err := c.Files("./testdata/env.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	for _, v := range []struct {
+		val, exp string
+	}{
+		{*host, "localhost"},
+		{*port, "9090"},
+		{*addr, "https://example.com:9090"},
+		{*tls, ""},
+		{*literal, "${XFLAG_TEST_HOST}"},
+	} {
+		if v.val != v.exp {
+			t.Errorf(`Expected "%v", got "%v".`, v.exp, v.val)
+		}
+	}
+}
+
+func TestContext_Files_EnvRequired(t *testing.T) {
+	for _, v := range []struct {
+		conf      config.Interface
+		file, exp string
+	}{
+		{
+			ini.New(nil), "./testdata/env_required.ini",
+			"./testdata/env_required.ini:2: XFLAG_TEST_DB_HOST: database host must be set",
+		},
+		{
+			json.New(nil), "./testdata/env.json",
+			"./testdata/env.json:3: XFLAG_TEST_DB_HOST: environment variable is not set",
+		},
+	} {
+		c := New(v.conf, nil)
+		if err := c.Files(v.file); err == nil || err.Error() != v.exp {
+			t.Errorf(`Expected "%s", got "%v".`, v.exp, err)
+		}
+	}
+
+	os.Setenv("XFLAG_TEST_DB_HOST", "db")
+	defer os.Unsetenv("XFLAG_TEST_DB_HOST")
+	c := New(json.New(nil), nil)
+	if err := c.Files("./testdata/env.json"); err != nil {
+		t.Errorf(`No error expected, got "%v".`, err)
+	}
+}
@@ -0,0 +1,111 @@
+package xflag
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// SetError represents a failure of flag.Value's Set method
+// that was called with a value from a configuration source.
+type SetError struct {
+	// Flag is a name of the flag the value was assigned to.
+	Flag string
+
+	// Value is the input that has been rejected by the flag.
+	Value string
+
+	// File is a path to the configuration file the value was
+	// taken from. It is empty if the value was received from
+	// the configuration passed to the New constructor.
+	File string
+
+	// Section is a name of the section (or object in terms of
+	// config.Interface) where the value was found. Default
+	// section is represented by an empty string.
+	Section string
+
+	// Line is a number of the line the value is defined on.
+	// It is zero if the line is unknown.
+	Line int
+
+	// Env is a name of the environment variable the value
+	// was taken from. It is empty if the value was
+	// received from a configuration file.
+	Env string
+
+	// Source is a name of the source and Location is a location
+	// of the value in it if the value was received from a Source.
+	Source, Location string
+
+	// Err is an error returned by the Set method.
+	Err error
+}
+
+// Error returns the SetError in a human readable format.
+// The default section is not mentioned if the file is known.
+func (e *SetError) Error() string {
+	var src string
+	switch {
+	case e.Source != "" || e.Location != "":
+		src = sourceOf(e.Source, e.Location)
+	case e.Env != "":
+		src = fmt.Sprintf(`environment variable "%s"`, e.Env)
+	case e.File != "":
+		src = fmt.Sprintf(`"%s"`, e.File)
+		if e.Section != "" {
+			src = fmt.Sprintf(`section "%s" of %s`, e.Section, src)
+		}
+		if e.Line > 0 {
+			src += fmt.Sprintf(`, line %d`, e.Line)
+		}
+	case e.Section == "":
+		src = "default section"
+	default:
+		src = fmt.Sprintf(`section "%s"`, e.Section)
+	}
+	return fmt.Sprintf(
+		`invalid value "%s" for flag "%s" (%s): %v`, e.Value, e.Flag, src, e.Err,
+	)
+}
+
+// sourceOf returns a description of the location of a value
+// in the Source with the name in a human readable format.
+func sourceOf(name, location string) string {
+	src := "unnamed source"
+	if name != "" {
+		src = fmt.Sprintf(`source "%s"`, name)
+	}
+	if location != "" {
+		src += fmt.Sprintf(`, %s`, location)
+	}
+	return src
+}
+
+// RequiredError is returned by ParseSet if some of the flags that
+// were marked as required have not been set by any source.
+type RequiredError struct {
+	// Flags is a list of names of the missing flags.
+	Flags []string
+}
+
+// Error returns the RequiredError in a human readable format.
+func (e *RequiredError) Error() string {
+	return fmt.Sprintf(`required flags are not set: "%s"`, strings.Join(e.Flags, `", "`))
+}
+
+// Errors is a list of errors that is returned by ParseSet
+// when it is necessary to report more than one failure at once.
+type Errors []error
+
+// Error returns all the errors of the list, one per line.
+func (es Errors) Error() string {
+	var buf bytes.Buffer
+	for i := range es {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(es[i].Error())
+	}
+	return buf.String()
+}
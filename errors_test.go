@@ -0,0 +1,121 @@
+package xflag
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/goaltools/xflag/cflag/types"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+var invalidFlags = flagDefs{
+	{"age", 18, ""},
+	{"ages[]", []int(nil), ""},
+	{"user:name", "", ""},
+	{"user:height", 180, ""},
+}
+
+func TestParseSet_SetErrors(t *testing.T) {
+	c := New(ini.New(nil), []string{})
+	if err := c.Files("./testdata/file1.ini", "./testdata/invalid.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+
+	err := c.ParseSet(invalidFlags.flagSet())
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf(`Errors expected, got "%v".`, err)
+	}
+	var res []SetError
+	for i := range errs {
+		e := *errs[i].(*SetError)
+		e.Err = nil
+		res = append(res, e)
+	}
+	exp := []SetError{
+		{Flag: "age", Value: "abc", File: "./testdata/invalid.ini", Line: 1},
+		{Flag: "ages[]", Value: "x", File: "./testdata/invalid.ini", Line: 3},
+		{Flag: "user:height", Value: "tall", File: "./testdata/invalid.ini", Section: "user", Line: 7},
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Errorf("Expected %v, got %v.", exp, res)
+	}
+}
+
+func TestParseSet_Warn(t *testing.T) {
+	var warns []string
+	c := New(ini.New(nil), []string{"--age", "33"})
+	c.Warn = func(err *SetError) {
+		warns = append(warns, err.Flag)
+	}
+	if err := c.Files("./testdata/invalid.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+
+	fset := invalidFlags.flagSet()
+	if err := c.ParseSet(fset); err != nil {
+		t.Errorf(`No error expected, got "%v".`, err)
+	}
+	if exp := []string{"age", "ages[]", "user:height"}; !reflect.DeepEqual(warns, exp) {
+		t.Errorf("Expected %v, got %v.", exp, warns)
+	}
+	if v := fset.Lookup("age").Value.String(); v != "33" {
+		t.Errorf(`Expected "33", got "%s".`, v)
+	}
+	if v := fset.Lookup("user:name").Value.String(); v != "James Bond" {
+		t.Errorf(`Expected "James Bond", got "%s".`, v)
+	}
+}
+
+func TestSetError_Error(t *testing.T) {
+	e := &SetError{Flag: "age", Value: "abc", Section: "user", File: "a.ini", Err: errors.New("oops")}
+	exp := `invalid value "abc" for flag "age" (section "user" of "a.ini"): oops`
+	if res := e.Error(); res != exp {
+		t.Errorf(`Expected "%s", got "%s".`, exp, res)
+	}
+	exp = exp + "\n" + exp
+	if res := (Errors{e, e}).Error(); res != exp {
+		t.Errorf(`Expected "%s", got "%s".`, exp, res)
+	}
+
+	e.Line = 7
+	exp = `invalid value "abc" for flag "age" (section "user" of "a.ini", line 7): oops`
+	if res := e.Error(); res != exp {
+		t.Errorf(`Expected "%s", got "%s".`, exp, res)
+	}
+
+	e = &SetError{Flag: "age", Value: "abc", File: "a.ini", Line: 2, Err: errors.New("oops")}
+	exp = `invalid value "abc" for flag "age" ("a.ini", line 2): oops`
+	if res := e.Error(); res != exp {
+		t.Errorf(`Expected "%s", got "%s".`, exp, res)
+	}
+
+	e = &SetError{Flag: "age", Value: "abc", Err: errors.New("oops")}
+	exp = `invalid value "abc" for flag "age" (default section): oops`
+	if res := e.Error(); res != exp {
+		t.Errorf(`Expected "%s", got "%s".`, exp, res)
+	}
+
+	e = &SetError{Flag: "age", Value: "abc", Location: "age", Err: errors.New("oops")}
+	exp = `invalid value "abc" for flag "age" (unnamed source, age): oops`
+	if res := e.Error(); res != exp {
+		t.Errorf(`Expected "%s", got "%s".`, exp, res)
+	}
+}
+
+func TestParseSet_EnumError(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.Var(&types.Enum{Value: "info", Choices: []string{"debug", "info"}}, "level", "")
+	c := New(ini.New(nil), nil)
+	if err := c.Files("./testdata/validate.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	exp := `invalid value "verbose" for flag "level" ("./testdata/validate.ini", line 2): ` +
+		`"verbose" is not one of "debug", "info"`
+	if err := c.ParseSet(fset); err == nil || err.Error() != exp {
+		t.Errorf(`Expected "%s", got "%v".`, exp, err)
+	}
+}
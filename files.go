@@ -0,0 +1,82 @@
+package xflag
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// FilePrefix is a prefix of values of the flags marked by the SecretFile
+// method that refer to files the actual values must be read from,
+// e.g. "@file:/run/secrets/db".
+const FilePrefix = "@file:"
+
+// readFile returns the value as is unless the flag with the
+// specified name is marked by the SecretFile method and the
+// value starts with the FilePrefix. Then the content of the file
+// without leading and trailing white space is returned.
+func (c *Context) readFile(name, v string) (string, error) {
+	if !c.marked(name, fileValue) || !strings.HasPrefix(v, FilePrefix) {
+		return v, nil
+	}
+	bs, err := ioutil.ReadFile(strings.TrimPrefix(v, FilePrefix))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(bs)), nil
+}
+
+// parseArgs parses the arguments of the Context using the flag set.
+// Values of the flags marked by the SecretFile method are read
+// from files if requested.
+func (c *Context) parseArgs(fset *flag.FlagSet) error {
+	args, err := c.fileArgs(fset)
+	if err != nil {
+		return err
+	}
+	return fset.Parse(args)
+}
+
+// fileArgs returns a copy of the arguments of the Context where values
+// of the flags marked by the SecretFile method that start with the
+// FilePrefix are replaced by the content of the files. Flags are found
+// the same way the flag package does: both "-name value" and
+// "-name=value" forms are supported (boolean flags don't take a value
+// unless the latter form is used), and the search stops at "--"
+// or the first non-flag argument.
+func (c *Context) fileArgs(fset *flag.FlagSet) ([]string, error) {
+	args := append(c.args[:0:0], c.args...)
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if len(a) < 2 || a[0] != '-' || a == "--" {
+			break
+		}
+		name := strings.TrimPrefix(a[1:], "-")
+		value, inline := "", false
+		if j := strings.Index(name, "="); j >= 0 {
+			name, value, inline = name[:j], name[j+1:], true
+		}
+		f := fset.Lookup(name)
+		if f == nil {
+			// The flag package reports the unknown flag.
+			break
+		}
+		if !inline {
+			if isBool(f) || i+1 == len(args) {
+				continue
+			}
+			i++
+			value = args[i]
+		}
+		v, err := c.readFile(name, value)
+		if err != nil {
+			return nil, fmt.Errorf(`invalid value "%s" for flag -%s: %v`, value, name, err)
+		}
+		if inline {
+			v = a[:len(a)-len(value)] + v
+		}
+		args[i] = v
+	}
+	return args, nil
+}
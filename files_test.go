@@ -0,0 +1,101 @@
+package xflag
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/goaltools/xflag/cflag/types"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+func TestContext_SecretFile(t *testing.T) {
+	os.Setenv("XFLAG_TEST_KEY", "@file:./testdata/secrets/token")
+	defer os.Unsetenv("XFLAG_TEST_KEY")
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	password := fset.String("password", "", "")
+	literal := fset.String("literal", "", "")
+	token := fset.String("token", "", "")
+	key := fset.String("key", "", "")
+
+	c := New(ini.New(nil), []string{"--token=@file:./testdata/secrets/token"})
+	c.Env("xflag_test")
+	c.SecretFile("password", "token", "key")
+	if err := c.Files("./testdata/secrets.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	for _, v := range []struct {
+		val, exp string
+	}{
+		{*password, "s3cr3t"},
+		{*literal, "@file:./testdata/secrets/db"},
+		{*token, "token"},
+		{*key, "token"},
+		{c.ProvenanceOf("password").Value, Redacted},
+		{c.ProvenanceOf("token").Value, Redacted},
+	} {
+		if v.val != v.exp {
+			t.Errorf(`Expected "%v", got "%v".`, v.exp, v.val)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := c.WriteINI(&buf, fset, true); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if exp := "key = REDACTED\n\nliteral = @file:./testdata/secrets/db\n\npassword = REDACTED\n\ntoken = REDACTED\n"; buf.String() != exp {
+		t.Errorf(`Expected "%s", got "%s".`, exp, buf.String())
+	}
+}
+
+func TestContext_SecretFile_Missing(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.SetOutput(ioutil.Discard)
+	fset.String("password", "", "")
+
+	c := New(ini.New(nil), []string{"--password=@file:./testdata/secrets/doesNotExist"})
+	c.SecretFile("password")
+	if err := c.ParseSet(fset); err == nil {
+		t.Errorf("Missing file: error expected.")
+	}
+}
+
+func TestContext_SecretFile_Help(t *testing.T) {
+	var buf bytes.Buffer
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.SetOutput(&buf)
+	fset.String("password", "", "password of the `user`")
+	fset.Bool("verbose", false, "")
+	fset.String("name", "", "")
+
+	c := New(ini.New(nil), []string{"-verbose", "-name", "x", "-h"})
+	c.SecretFile("password", "name")
+	if err := c.ParseSet(fset); err != flag.ErrHelp {
+		t.Errorf(`Expected "%v", got "%v".`, flag.ErrHelp, err)
+	}
+	if exp := "  -password user\n"; !strings.Contains(buf.String(), exp) {
+		t.Errorf(`Expected "%s" in the help, got "%s".`, exp, buf.String())
+	}
+}
+
+func TestContext_Secret_SetError(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.Var(&types.Ints{}, "pin[]", "")
+
+	c := New(ini.New(nil), nil)
+	c.Secret("pin[]")
+	c.Sources(&Map{Name: "test", Values: map[string][]string{"pin": {"s3cr3t"}}})
+	exp := `invalid value "REDACTED" for flag "pin[]" (source "test", pin): ` +
+		`strconv.Atoi: parsing "REDACTED": invalid syntax`
+	if err := c.ParseSet(fset); err == nil || err.Error() != exp {
+		t.Errorf(`Expected "%s", got "%v".`, exp, err)
+	}
+}
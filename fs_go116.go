@@ -0,0 +1,40 @@
+//go:build go1.16
+// +build go1.16
+
+package xflag
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/conveyer/config"
+)
+
+// FSConfig is an optional interface that may be implemented by
+// configurations (config.Interface) that can be read from a file
+// system, e.g. the INI configuration. It is required by the FS method.
+type FSConfig interface {
+	// NewFS should open, parse, and process the file with the name
+	// from the file system, allocate a new config, and return it.
+	NewFS(fsys fs.FS, name string) (config.Interface, error)
+}
+
+// FS is an equivalent of the Files method that reads the files with the
+// names from the file system, e.g. embed.FS with defaults compiled into
+// a binary. Names are slash-separated paths as required by the fs package.
+// The configuration the Context was allocated with must implement the
+// FSConfig interface. Such files are not reloaded by Watcher.
+func (c *Context) FS(fsys fs.FS, names ...string) error {
+	fc, ok := c.conf.(FSConfig)
+	if !ok {
+		return fmt.Errorf(`configuration of type "%T" cannot be read from fs.FS`, c.conf)
+	}
+	for i := range names {
+		conf, err := fc.NewFS(fsys, names[i])
+		if err != nil {
+			return err
+		}
+		c.files = append(c.files, file{name: names[i], conf: conf, fixed: true})
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+//go:build go1.16
+// +build go1.16
+
+package xflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+func TestContext_FS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"conf/main.ini":      {Data: []byte("include = base.ini\nname = main\n")},
+		"conf/base.ini":      {Data: []byte("name = base\nport = 80\n@include conf.d/*.ini\n")},
+		"conf/conf.d/10.ini": {Data: []byte("# Port.\nport = 8080\n")},
+		"conf/bad.ini":       {Data: []byte("include = conf.d/10.ini\n[section\n")},
+	}
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	name := fset.String("name", "", "")
+	port := fset.Int("port", 0, "")
+
+	c := New(ini.New(nil), nil)
+	if err := c.FS(fsys, "conf/main.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if *name != "main" || *port != 8080 {
+		t.Errorf(`Expected "main" and "8080", got "%s" and "%d".`, *name, *port)
+	}
+	exp := Origin{Kind: FromFile, Value: "8080", File: "conf/conf.d/10.ini", Line: 2}
+	if p := c.ProvenanceOf("port"); p == nil || p.Origin != exp {
+		t.Errorf(`Expected "%+v", got "%+v".`, exp, p)
+	}
+
+	for _, v := range []struct {
+		name, exp string
+	}{
+		{"conf/bad.ini", `failed to parse "conf/bad.ini": ini syntax error on line 2: `},
+		{"conf/doesNotExist.ini", `open conf/doesNotExist.ini: `},
+	} {
+		err := New(ini.New(nil), nil).FS(fsys, v.name)
+		if err == nil || !strings.HasPrefix(err.Error(), v.exp) {
+			t.Errorf(`Expected "%s...", got "%v".`, v.exp, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package xflag
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/goaltools/xflag/cflag/types"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+func TestContext_Files_Include(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	name := fset.String("name", "", "")
+	verbose := fset.Bool("verbose", false, "")
+	port := fset.Int("database:port", 0, "")
+	user := fset.String("database:user", "", "")
+	password := fset.String("database:password", "", "")
+	hosts := &types.Strings{}
+	fset.Var(hosts, "database:hosts[]", "")
+
+	c := New(ini.New(nil), nil)
+	if err := c.Files("./testdata/include/main.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	for _, v := range []struct {
+		exp, res interface{}
+	}{
+		{"base", *name},
+		{true, *verbose},
+		{5433, *port},
+		{"admin", *user},
+		{"b", *password},
+		{"[b1]", hosts.String()},
+	} {
+		if v.res != v.exp {
+			t.Errorf(`Expected "%v", got "%v".`, v.exp, v.res)
+		}
+	}
+
+	for _, v := range []struct {
+		flag string
+		exp  Origin
+	}{
+		{"name", Origin{Kind: FromFile, Value: "base", File: "testdata/include/base.ini", Line: 1}},
+		{"database:port", Origin{Kind: FromFile, Value: "5433", File: "./testdata/include/main.ini", Section: "database", Line: 6}},
+		{"database:hosts[]", Origin{Kind: FromFile, Value: "[b1]", File: "testdata/include/conf.d/b.ini", Section: "database", Line: 2}},
+	} {
+		if p := c.ProvenanceOf(v.flag); p == nil || p.Origin != v.exp {
+			t.Errorf(`Flag "%s": expected "%+v", got "%+v".`, v.flag, v.exp, p)
+		}
+	}
+}
+
+func TestContext_Files_IncludeErrors(t *testing.T) {
+	for _, v := range []struct {
+		file, exp string
+	}{
+		{
+			"./testdata/include/cycle1.ini",
+			"include cycle: ./testdata/include/cycle1.ini:1 -> testdata/include/cycle2.ini:1 -> testdata/include/cycle1.ini",
+		},
+		{
+			"./testdata/include/missing1.ini",
+			`failed to include "testdata/include/absent.ini" (./testdata/include/missing1.ini:2 -> testdata/include/missing2.ini:1): ` +
+				"open testdata/include/absent.ini: no such file or directory",
+		},
+		{
+			"./testdata/include/directive.ini",
+			`failed to parse "./testdata/include/directive.ini": ini syntax error on line 3: unknown directive "@foo"`,
+		},
+	} {
+		c := New(ini.New(nil), nil)
+		if err := c.Files(v.file); err == nil || err.Error() != v.exp {
+			t.Errorf(`Expected "%s", got "%v".`, v.exp, err)
+		}
+	}
+}
+
+func TestContext_Files_IncludeArrays(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	names := &types.Strings{}
+	fset.Var(names, "names[]", "")
+	hosts := &types.Strings{}
+	fset.Var(hosts, "database:hosts[]", "")
+	include := fset.String("paths:include", "", "")
+	port := fset.Int("database:port", 0, "")
+
+	c := New(ini.New(nil), nil)
+	if err := c.Files("./testdata/include/arrays.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	for _, v := range []struct {
+		exp, res interface{}
+	}{
+		{"[a; b]", names.String()},
+		{"[c1]", hosts.String()},
+		{"/usr/include", *include},
+		{5432, *port},
+	} {
+		if v.res != v.exp {
+			t.Errorf(`Expected "%v", got "%v".`, v.exp, v.res)
+		}
+	}
+}
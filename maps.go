@@ -0,0 +1,128 @@
+package xflag
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/conveyer/config"
+)
+
+// isMap returns true if the flag name ends with the map literal.
+func (c *Context) isMap(name string) bool {
+	return c.MapLiteral != "" && strings.HasSuffix(name, c.MapLiteral)
+}
+
+// mapKey returns a key of the map flag with the specified name
+// if n is of the "name{key}" form. False is returned as a second
+// argument otherwise.
+func (c *Context) mapKey(n, name string) (string, bool) {
+	l := len(c.MapLiteral) / 2
+	open, close := c.MapLiteral[:l], c.MapLiteral[l:]
+	if len(n) <= len(name)+len(c.MapLiteral) || !strings.HasPrefix(n, name+open) || !strings.HasSuffix(n, close) {
+		return "", false
+	}
+	return n[len(name)+len(open) : len(n)-len(close)], true
+}
+
+// lookupMap receives "key=value" pairs of the map flag with the path.
+// Pairs of all the files are merged, pairs of subsequent files override
+// the pairs of the previous ones with the same keys. The result is sorted
+// by keys. Location of the pairs of the last file that has any
+// of them is returned as a second argument.
+// False is returned as a third argument if there are no pairs.
+func (c *Context) lookupMap(path []string) ([]string, Origin, bool) {
+	o := Origin{Kind: FromFile, Section: section(path)}
+	m := map[string]string{}
+	add := func(conf config.Interface, name string) {
+		ss, line, f := c.pairs(conf, path)
+		if len(ss) == 0 {
+			return
+		}
+		o.File, o.Line = name, line
+		if f != "" {
+			o.File = f
+		}
+		for i := range ss {
+			k := ss[i][:strings.Index(ss[i], "=")]
+			m[k] = ss[i]
+		}
+	}
+	add(c.conf, "")
+	for i := range c.files {
+		add(c.files[i].conf, c.files[i].name)
+	}
+
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	ss := make([]string, len(ks))
+	for i, k := range sorted(ks) {
+		ss[i] = m[k]
+	}
+	return ss, o, len(ss) > 0
+}
+
+// pairs returns "key=value" pairs of the map flag with the path
+// that are defined in the configuration. Keys of the section
+// (object) with the path go first, keys of the "name{key}"
+// form in the flag's section override them.
+// A number of the line the first pair is defined on and
+// the file it is defined in (if the configuration implements
+// FileLocator) are returned as the second and third arguments.
+func (c *Context) pairs(conf config.Interface, path []string) (ss []string, line int, file string) {
+	add := func(k string, v config.ValueInterface, l int, f string) {
+		s, ok := v.String()
+		if !ok {
+			return
+		}
+		if len(ss) == 0 {
+			line, file = l, f
+		}
+		ss = append(ss, k+"="+s)
+	}
+
+	// Whole section form, e.g.:
+	//	[labels]
+	//	env = prod
+	obj := conf.At(path...)
+	for _, k := range sorted(conf.Names(path...)) {
+		v := obj.Value(k)
+		l := 0
+		if loc, ok := obj.(Locator); ok {
+			l = loc.Line(k)
+		}
+		add(k, v, l, fileOf(obj, []string{k}, ""))
+	}
+
+	// Key form, e.g.:
+	//	labels{env} = prod
+	sect, name := path[:len(path)-1], path[len(path)-1]
+	names := conf.Names(sect...)
+	if len(sect) == 0 {
+		// Keys of INI's default section are listed
+		// as the section with empty name.
+		names = append(names, conf.Names("")...)
+	}
+	for _, n := range sorted(names) {
+		if k, ok := c.mapKey(n, name); ok {
+			p := append(sect[:len(sect):len(sect)], n)
+			v, l := value(conf, p)
+			add(k, v, l, fileOf(conf, p, ""))
+		}
+	}
+	return
+}
+
+// sorted returns a sorted copy of the list without duplicates.
+func sorted(ss []string) (res []string) {
+	m := map[string]bool{}
+	for i := range ss {
+		if !m[ss[i]] {
+			m[ss[i]] = true
+			res = append(res, ss[i])
+		}
+	}
+	sort.Strings(res)
+	return
+}
@@ -0,0 +1,140 @@
+package xflag
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/goaltools/xflag/cflag/types"
+	"github.com/goaltools/xflag/config/json"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+var mapFlags = flagDefs{
+	{"labels{}", map[string]string(nil), ""},
+	{"server:limits{}", map[string]int(nil), ""},
+	{"empty{}", map[string]string{"a": "b"}, ""},
+}
+
+func TestParseSet_Maps(t *testing.T) {
+	fset := mapFlags.flagSet()
+	c := New(ini.New(nil), nil)
+	c.Strict = true
+	if err := c.Files("./testdata/maps.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	for name, exp := range map[string]string{
+		"labels{}":        "[env=prod; team=core]",
+		"server:limits{}": "[cpu=2; memory=512]",
+		"empty{}":         "[a=b]",
+	} {
+		if res := fset.Lookup(name).Value.String(); res != exp {
+			t.Errorf(`"%s": Expected "%s", got "%s".`, name, exp, res)
+		}
+	}
+	if p := c.ProvenanceOf("server:limits{}"); p.File != "./testdata/maps.ini" || p.Line != 6 {
+		t.Errorf(`Incorrect provenance of the flag: %+v.`, *p)
+	}
+}
+
+func TestParseSet_MapsOverride(t *testing.T) {
+	os.Setenv("XFLAG_TEST_SERVER_LIMITS", "cpu=8,disk=10")
+	defer os.Unsetenv("XFLAG_TEST_SERVER_LIMITS")
+
+	fset := mapFlags.flagSet()
+	c := New(ini.New(nil), []string{"--labels{}", "env=test"})
+	c.Env("xflag_test")
+	if err := c.Files("./testdata/maps.ini", "./testdata/maps_tenant.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	for name, exp := range map[string]string{
+		"labels{}":        "[env=test]",
+		"server:limits{}": "[cpu=8; disk=10]",
+	} {
+		if res := fset.Lookup(name).Value.String(); res != exp {
+			t.Errorf(`"%s": Expected "%s", got "%s".`, name, exp, res)
+		}
+	}
+}
+
+func TestParseSet_MapsMerge(t *testing.T) {
+	fset := mapFlags.flagSet()
+	c := New(ini.New(nil), nil)
+	if err := c.Files("./testdata/maps.ini", "./testdata/maps_tenant.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	for name, exp := range map[string]string{
+		"labels{}":        "[env=prod; team=platform]",
+		"server:limits{}": "[cpu=2; memory=1024]",
+	} {
+		if res := fset.Lookup(name).Value.String(); res != exp {
+			t.Errorf(`"%s": Expected "%s", got "%s".`, name, exp, res)
+		}
+	}
+	if p := c.ProvenanceOf("server:limits{}"); p.File != "./testdata/maps_tenant.ini" || p.Line != 5 {
+		t.Errorf(`Incorrect provenance of the flag: %+v.`, *p)
+	}
+}
+
+func TestParseJSON_Maps(t *testing.T) {
+	fset := mapFlags.flagSet()
+	c := New(json.New(nil), nil)
+	c.Strict = true
+	if err := c.Files("./testdata/maps.json"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	for name, exp := range map[string]string{
+		"labels{}":        "[env=dev]",
+		"server:limits{}": "[cpu=4]",
+	} {
+		if res := fset.Lookup(name).Value.String(); res != exp {
+			t.Errorf(`"%s": Expected "%s", got "%s".`, name, exp, res)
+		}
+	}
+}
+
+func TestParseSet_MapsStrict(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.Var(&types.StringMap{}, "server:limitz{}", "")
+	c := New(ini.New(nil), nil)
+	c.Strict = true
+	if err := c.Files("./testdata/maps.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	errs, ok := c.ParseSet(fset).(Errors)
+	if !ok || len(errs) != 4 {
+		t.Errorf(`Four unknown keys expected, got "%v".`, errs)
+	}
+}
+
+func TestContext_WriteSample_Maps(t *testing.T) {
+	fset := mapFlags.flagSet()
+	var buf bytes.Buffer
+	if err := New(ini.New(nil), nil).WriteSample(&buf, fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	exp := `empty{a} = b
+
+# labels{} =
+
+[server]
+# limits{} =
+`
+	if res := buf.String(); res != exp {
+		t.Errorf(`Expected "%s", got "%s".`, exp, res)
+	}
+}
@@ -0,0 +1,62 @@
+package xflag
+
+// mark is a set of attributes of a flag that
+// change the way it is processed.
+type mark int
+
+// Attributes of flags.
+const (
+	secret    mark = 1 << iota // Value of the flag must not be revealed.
+	required                   // Value of the flag must be set by some source.
+	filePath                   // Value of the flag is a path to a file.
+	fileValue                  // Value of the flag may be read from a file.
+)
+
+// Secret marks the flags with the specified names as secret.
+// Values of such flags are redacted by WriteINI and WriteJSON
+// if requested.
+func (c *Context) Secret(names ...string) {
+	c.mark(secret, names)
+}
+
+// Require marks the flags with the specified names as required.
+// ParseSet returns a *RequiredError if values of such flags are
+// not set by any source: configuration files, environment
+// variables, or command line arguments.
+func (c *Context) Require(names ...string) {
+	c.mark(required, names)
+}
+
+// SecretFile marks the flags with the specified names as secret
+// (see Secret) and allows their values to be read from files.
+// A value of such a flag of the "@file:path" form (see FilePrefix)
+// is replaced by the content of the file with leading and trailing
+// white space removed. That works for values of configuration files,
+// environment variables, and command line arguments, e.g.:
+//	password = @file:/run/secrets/db
+//	--password=@file:/run/secrets/db
+// Values of other flags that start with the FilePrefix are not
+// treated in a special way.
+func (c *Context) SecretFile(names ...string) {
+	c.mark(secret|fileValue, names)
+}
+
+// Path marks the flags with the specified names as paths to files.
+// Shell completion scripts complete values of such flags
+// with names of files.
+func (c *Context) Path(names ...string) {
+	c.mark(filePath, names)
+}
+
+// mark adds the attribute m to the flags with the specified names.
+func (c *Context) mark(m mark, names []string) {
+	for i := range names {
+		c.marks[names[i]] |= m
+	}
+}
+
+// marked returns whether the flag with the specified
+// name has the attribute m.
+func (c *Context) marked(name string, m mark) bool {
+	return c.marks[name]&m != 0
+}
@@ -0,0 +1,190 @@
+package xflag
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/conveyer/config"
+)
+
+// Kind is a type of the source a value of flag was received from.
+type Kind int
+
+// Kinds of sources of values of flags.
+const (
+	FromDefault Kind = iota // FromDefault is used for default values of flags.
+	FromFile                // FromFile is used for values of configuration files.
+	FromEnv                 // FromEnv is used for values of environment variables.
+	FromArgs                // FromArgs is used for command line arguments.
+	FromSource              // FromSource is used for values of other sources (see Source).
+)
+
+var kinds = [...]string{"default", "file", "env", "args", "source"}
+
+// String returns the kind in a human readable format.
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kinds) {
+		return "unknown"
+	}
+	return kinds[k]
+}
+
+// Origin describes a value that was assigned to a flag
+// and the place it was received from.
+type Origin struct {
+	// Kind is a type of the source of the value.
+	Kind Kind
+
+	// Value is the value of the flag in a human readable format
+	// (as returned by the String method of flag.Value) right
+	// after it was assigned. Values of files that are overridden
+	// by subsequent files are not assigned to the flag, so they
+	// are recorded as is, e.g. "[a b]" for arrays.
+	Value string
+
+	// File is a path to the configuration file and Section is
+	// a name of its section the value was found in, if the
+	// Kind is FromFile. File is empty for the configuration
+	// passed to the New constructor.
+	File, Section string
+
+	// Line is a number of the line the value is defined on, if
+	// the Kind is FromFile and the configuration implements
+	// the Locator interface. It is zero otherwise.
+	Line int
+
+	// Env is a name of the environment variable the value
+	// was received from, if the Kind is FromEnv.
+	Env string
+
+	// Source is a name of the source and Location is a location
+	// of the value in it, as returned by the Lookup method of
+	// the Source, if the Kind is FromSource.
+	Source, Location string
+}
+
+// Provenance describes where the value of a flag came from.
+type Provenance struct {
+	// Flag is a name of the flag.
+	Flag string
+
+	// Origin describes the final value of the flag.
+	Origin
+
+	// Overridden is a list of the values the final one has
+	// overridden, starting with the default value.
+	Overridden []Origin
+}
+
+// Locator is an optional interface that may be implemented by
+// configurations (config.Interface) that know where their
+// values are defined. If implemented, line numbers are included
+// into the provenance of flags and errors.
+type Locator interface {
+	// Line should return a number of the line (starting from 1)
+	// the value with the specified element path is defined on,
+	// or zero if that is unknown.
+	Line(elementPath ...string) int
+}
+
+// FileLocator is an optional interface that may be implemented by
+// configurations whose values may be defined in other files than
+// the one that was opened, e.g. INI files with include directives.
+// If implemented, paths of those files are reported instead.
+type FileLocator interface {
+	// File should return a path of the file the value with the
+	// specified element path is defined in, or an empty string
+	// if that is unknown.
+	File(elementPath ...string) string
+}
+
+// fileOf returns a path of the file the value with the path
+// is defined in if the configuration implements FileLocator.
+// The name is returned otherwise.
+func fileOf(conf config.Interface, path []string, name string) string {
+	obj, path := at(conf, path)
+	if l, ok := obj.(FileLocator); ok {
+		if f := l.File(path...); f != "" {
+			return f
+		}
+	}
+	return name
+}
+
+// Provenance returns information about the origins of values of
+// all the flags processed by ParseSet, sorted by flag names.
+// Use the Provenance method of Watcher while the files are watched.
+func (c *Context) Provenance() []*Provenance {
+	names := make([]string, 0, len(c.origins))
+	for name := range c.origins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := make([]*Provenance, len(names))
+	for i := range names {
+		res[i] = c.ProvenanceOf(names[i])
+	}
+	return res
+}
+
+// ProvenanceOf returns information about the origin of the value of
+// the flag with the requested name or nil if the flag hasn't
+// been processed by ParseSet.
+func (c *Context) ProvenanceOf(name string) *Provenance {
+	os, ok := c.origins[name]
+	if !ok {
+		return nil
+	}
+	l := len(os) - 1
+	return &Provenance{
+		Flag:       name,
+		Origin:     os[l],
+		Overridden: append([]Origin{}, os[:l]...),
+	}
+}
+
+// missing returns a *RequiredError if some of the flags that are
+// marked as required still have their default values, i.e.
+// haven't been set by any source.
+func (c *Context) missing(fset *flag.FlagSet) error {
+	var names []string
+	fset.VisitAll(func(f *flag.Flag) {
+		if !c.marked(f.Name, required) {
+			return
+		}
+		if p := c.ProvenanceOf(f.Name); p == nil || p.Kind == FromDefault {
+			names = append(names, f.Name)
+		}
+	})
+	if len(names) > 0 {
+		return &RequiredError{Flags: names}
+	}
+	return nil
+}
+
+// recordRaw adds the origin of the values that are not assigned
+// to the flag, e.g. because they are overridden, to its history.
+// The values are recorded as is.
+func (c *Context) recordRaw(f *flag.Flag, ss []string, arr bool, o Origin) {
+	switch {
+	case c.marked(f.Name, secret):
+		o.Value = Redacted
+	case arr:
+		o.Value = fmt.Sprint(ss)
+	default:
+		o.Value = ss[0]
+	}
+	c.origins[f.Name] = append(c.origins[f.Name], o)
+}
+
+// record adds the origin of the current value of
+// the flag to its history.
+func (c *Context) record(f *flag.Flag, o Origin) {
+	o.Value = f.Value.String()
+	if c.marked(f.Name, secret) {
+		o.Value = Redacted
+	}
+	c.origins[f.Name] = append(c.origins[f.Name], o)
+}
@@ -0,0 +1,145 @@
+package xflag
+
+import (
+	"flag"
+	"go/build"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goaltools/xflag/cflag/types"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+func TestContext_Provenance(t *testing.T) {
+	os.Setenv("XFLAG_TEST_SECTION_KEY1", "env_value")
+	defer os.Unsetenv("XFLAG_TEST_SECTION_KEY1")
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.String("key1", "default", "")
+	fset.Var(&types.Strings{}, "key2[]", "")
+	fset.String("section:key1", "", "")
+	fset.String("section:key2", "", "")
+	fset.String("untouched", "default", "")
+
+	c := New(ini.New(nil), []string{"--section:key2", "arg_value"})
+	c.Env("xflag_test")
+	err := c.Files("./testdata/file1.ini", "./testdata/file2.ini", "./testdata/file3.ini")
+	if err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+
+	def := Origin{Kind: FromDefault, Value: "default"}
+	empty := Origin{Kind: FromDefault}
+	exp := []*Provenance{
+		{
+			Flag: "key1",
+			Origin: Origin{
+				Kind: FromFile, Value: "overridden", File: "./testdata/file3.ini", Line: 1,
+			},
+			Overridden: []Origin{def, {
+				Kind: FromFile, Value: build.Default.GOPATH, File: "./testdata/file1.ini", Line: 1,
+			}},
+		},
+		{
+			Flag: "key2[]",
+			Origin: Origin{
+				Kind: FromFile, Value: "[value2; value2_1]", File: "./testdata/file1.ini", Line: 3,
+			},
+			Overridden: []Origin{{Kind: FromDefault, Value: "[]"}},
+		},
+		{
+			Flag:       "section:key1",
+			Origin:     Origin{Kind: FromEnv, Value: "env_value", Env: "XFLAG_TEST_SECTION_KEY1"},
+			Overridden: []Origin{empty, {Kind: FromFile, Value: "value2", File: "./testdata/file2.ini", Section: "section", Line: 2}},
+		},
+		{
+			Flag:       "section:key2",
+			Origin:     Origin{Kind: FromArgs, Value: "arg_value"},
+			Overridden: []Origin{empty, {Kind: FromFile, Value: "value3", File: "./testdata/file3.ini", Section: "section", Line: 4}},
+		},
+		{
+			Flag:       "untouched",
+			Origin:     def,
+			Overridden: []Origin{},
+		},
+	}
+	res := c.Provenance()
+	if len(res) != len(exp) {
+		t.Fatalf("Expected %d flags, got %d.", len(exp), len(res))
+	}
+	for i := range exp {
+		if !reflect.DeepEqual(res[i], exp[i]) {
+			t.Errorf("Expected %+v, got %+v.", *exp[i], *res[i])
+		}
+	}
+	if res := c.ProvenanceOf("key1"); !reflect.DeepEqual(res, exp[0]) {
+		t.Errorf("Expected %+v, got %+v.", *exp[0], res)
+	}
+	if res := c.ProvenanceOf("unknown"); res != nil {
+		t.Errorf("Expected nil, got %v.", res)
+	}
+}
+
+// accumulator is a flag.Value that keeps all the values it gets.
+type accumulator []string
+
+func (a *accumulator) String() string     { return strings.Join(*a, " ") }
+func (a *accumulator) Set(v string) error { *a = append(*a, v); return nil }
+
+func TestParseSet_OverriddenFiles(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	age := fset.Int("age", 0, "")
+	password := fset.String("password", "", "")
+	url := fset.String("url", "", "")
+	names := &accumulator{}
+	fset.Var(names, "names", "")
+
+	c := New(ini.New(nil), nil)
+	c.SecretFile("password")
+	if err := c.Files("./testdata/override1.ini", "./testdata/override2.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	for _, v := range []struct {
+		exp, res interface{}
+	}{
+		{30, *age},
+		{"s3cr3t", *password},
+		{"http://example.com", *url},
+		{"b", names.String()},
+	} {
+		if v.res != v.exp {
+			t.Errorf(`Expected "%v", got "%v".`, v.exp, v.res)
+		}
+	}
+
+	exp := []Origin{
+		{Kind: FromDefault, Value: "0"},
+		{Kind: FromFile, Value: "abc", File: "./testdata/override1.ini", Line: 1},
+	}
+	if p := c.ProvenanceOf("age"); !reflect.DeepEqual(p.Overridden, exp) {
+		t.Errorf("Expected %+v, got %+v.", exp, p.Overridden)
+	}
+}
+
+func TestKind_String(t *testing.T) {
+	for k, exp := range map[Kind]string{
+		FromDefault: "default",
+		FromFile:    "file",
+		FromEnv:     "env",
+		FromArgs:    "args",
+		Kind(-1):    "unknown",
+	} {
+		if res := k.String(); res != exp {
+			t.Errorf(`Expected "%s", got "%s".`, exp, res)
+		}
+	}
+}
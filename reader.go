@@ -0,0 +1,37 @@
+package xflag
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/conveyer/config"
+)
+
+// ReaderConfig is an optional interface that may be implemented by
+// configurations (config.Interface) that can be read from io.Reader,
+// e.g. the INI configuration. It is required by the Reader method.
+type ReaderConfig interface {
+	// NewReader should parse the configuration read from the r,
+	// allocate a new config, and return it. The name is a logical
+	// path of the file that is expected to be used in error messages.
+	NewReader(r io.Reader, name string) (config.Interface, error)
+}
+
+// Reader reads a configuration from the r and parses it as if it was
+// a file passed to the Files method, e.g. configuration of tests or
+// defaults that are compiled into a binary. The name is a logical path
+// of the file that is used in error messages and the provenance.
+// The configuration the Context was allocated with must implement
+// the ReaderConfig interface. Such files are not reloaded by Watcher.
+func (c *Context) Reader(r io.Reader, name string) error {
+	rc, ok := c.conf.(ReaderConfig)
+	if !ok {
+		return fmt.Errorf(`configuration of type "%T" cannot be read from io.Reader`, c.conf)
+	}
+	conf, err := rc.NewReader(r, name)
+	if err != nil {
+		return err
+	}
+	c.files = append(c.files, file{name: name, conf: conf, fixed: true})
+	return nil
+}
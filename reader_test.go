@@ -0,0 +1,46 @@
+package xflag
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/goaltools/xflag/config/json"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+func TestContext_Reader(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	name := fset.String("name", "", "")
+	port := fset.Int("database:port", 0, "")
+
+	c := New(ini.New(nil), nil)
+	err := c.Reader(strings.NewReader("name = reader\n\n[database]\nport = 5432\n"), "defaults.ini")
+	if err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if *name != "reader" || *port != 5432 {
+		t.Errorf(`Expected "reader" and "5432", got "%s" and "%d".`, *name, *port)
+	}
+	exp := Origin{Kind: FromFile, Value: "5432", File: "defaults.ini", Section: "database", Line: 4}
+	if p := c.ProvenanceOf("database:port"); p == nil || p.Origin != exp {
+		t.Errorf(`Expected "%+v", got "%+v".`, exp, p)
+	}
+}
+
+func TestContext_Reader_Errors(t *testing.T) {
+	c := New(ini.New(nil), nil)
+	exp := `failed to parse "defaults.ini": ini syntax error on line 2: "=" separator is missing after the key "name"`
+	if err := c.Reader(strings.NewReader("# Comment.\nname\n"), "defaults.ini"); err == nil || err.Error() != exp {
+		t.Errorf(`Expected "%s", got "%v".`, exp, err)
+	}
+
+	c = New(json.New(nil), nil)
+	if err := c.Reader(strings.NewReader("{}"), "defaults.json"); err == nil {
+		t.Errorf("Configuration without NewReader method: error expected.")
+	}
+}
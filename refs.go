@@ -0,0 +1,125 @@
+package xflag
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// setResolved replaces references to other keys of the configuration
+// in the values received from the origin and assigns them to the flag
+// using the set method. References are of the "${section:key}" form,
+// i.e. they are names of flags with the Separator, and "${:key}" refers
+// to a key of the default section. Names without the Separator are not
+// references and are left as is (they are environment variables that
+// are expanded by configurations when files are parsed).
+// The referenced keys are looked up the same way values of flags are,
+// so a value of a subsequent file overrides the referenced one of
+// a previous file. Referenced values may contain references themselves.
+// The "$${section:key}" is replaced by "${section:key}" and is not
+// a reference. Configurations unescape "$${NAME}" of environment
+// variables themselves (see config.Expand), so "${NAME:-default}"
+// and "$${NAME}" are left as is.
+// Values with references that cannot be resolved, e.g. due to
+// cycles, are reported as *SetError.
+func (c *Context) setResolved(f *flag.Flag, ss []string, arr bool, o Origin) []*SetError {
+	rs := make([]string, len(ss))
+	for i := range ss {
+		s, err := c.interpolate(ss[i], nil)
+		if err != nil {
+			value := ss[i]
+			if c.marked(f.Name, secret) {
+				value = Redacted
+			}
+			return []*SetError{{
+				Flag:    f.Name,
+				Value:   value,
+				File:    o.File,
+				Section: o.Section,
+				Line:    o.Line,
+				Err:     err,
+			}}
+		}
+		rs[i] = s
+	}
+	return c.set(f, rs, arr, o)
+}
+
+// interpolate replaces references in the string. The stack is a list
+// of the references that are being resolved and is used for detection
+// of cycles.
+func (c *Context) interpolate(s string, stack []string) (string, error) {
+	var buf bytes.Buffer
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			buf.WriteString(s)
+			return buf.String(), nil
+		}
+
+		// Make sure the reference is terminated
+		// and is not an environment variable.
+		end := strings.Index(s[i:], "}")
+		if end < 0 {
+			buf.WriteString(s)
+			return buf.String(), nil
+		}
+		end += i
+		name := s[i+2 : end]
+		if !c.isReference(name) {
+			buf.WriteString(s[:end+1])
+			s = s[end+1:]
+			continue
+		}
+
+		// Escaped "$${section:key}" is replaced by "${section:key}".
+		if i > 0 && s[i-1] == '$' {
+			buf.WriteString(s[:i-1] + s[i:end+1])
+			s = s[end+1:]
+			continue
+		}
+		buf.WriteString(s[:i])
+
+		v, err := c.reference(name, stack)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(v)
+		s = s[end+1:]
+	}
+}
+
+// reference returns a value of the key with the name
+// that references in it are replaced.
+func (c *Context) reference(name string, stack []string) (string, error) {
+	for i := range stack {
+		if stack[i] == name {
+			return "", fmt.Errorf(
+				"reference cycle: %s -> %s", strings.Join(stack[i:], " -> "), name,
+			)
+		}
+	}
+	path := strings.Split(strings.TrimPrefix(name, c.Separator), c.Separator)
+	v, _ := c.lookup(path)
+	if v.Interface() == nil {
+		return "", fmt.Errorf(`reference to unknown key "%s"`, name)
+	}
+	s, ok := v.String()
+	if !ok {
+		return "", fmt.Errorf(`reference to key "%s" that is not a string`, name)
+	}
+	return c.interpolate(s, append(stack[:len(stack):len(stack)], name))
+}
+
+// isReference checks whether the name of the "${name}" is a reference
+// to a key rather than an environment variable, i.e. it contains
+// the Separator that is not followed by an operator of config.Expand.
+func (c *Context) isReference(name string) bool {
+	i := strings.Index(name, c.Separator)
+	if i < 0 {
+		return false
+	}
+	rest := name[i+len(c.Separator):]
+	return c.Separator != ":" || rest == "" || strings.IndexByte("-?+", rest[0]) < 0
+}
@@ -0,0 +1,53 @@
+package xflag
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+func TestContext_ParseSet_References(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	url := fset.String("url", "", "")
+	name := fset.String("name", "", "")
+	literal := fset.String("literal", "", "")
+	home := fset.String("home", "", "")
+	fallback := fset.String("fallback", "", "")
+	fset.String("cycle", "", "")
+	fset.String("missing", "", "")
+
+	c := New(ini.New(nil), nil)
+	if err := c.Files("./testdata/refs1.ini", "./testdata/refs2.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	err := c.ParseSet(fset)
+	for _, v := range []struct {
+		val, exp string
+	}{
+		{*url, "http://example.com:8080/api"},
+		{*name, "my-app"},
+		{*literal, "${server:host}"},
+		{*home, "${HOME}"},
+		{*fallback, "${HOME:-none}"},
+	} {
+		if v.val != v.exp {
+			t.Errorf(`Expected "%v", got "%v".`, v.exp, v.val)
+		}
+	}
+
+	errs, ok := err.(Errors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf(`Two errors expected, got "%v".`, err)
+	}
+	for i, exp := range []string{
+		`invalid value "${:loop1}" for flag "cycle" ("./testdata/refs1.ini", line 4): ` +
+			`reference cycle: :loop1 -> :loop2 -> :loop1`,
+		`invalid value "${server:unknown}" for flag "missing" ("./testdata/refs1.ini", line 7): ` +
+			`reference to unknown key "server:unknown"`,
+	} {
+		if r := errs[i].Error(); r != exp {
+			t.Errorf(`Expected "%s", got "%s".`, exp, r)
+		}
+	}
+}
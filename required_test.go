@@ -0,0 +1,58 @@
+package xflag
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+var requiredFlags = flagDefs{
+	{"key1", "", ""},
+	{"from_env", "", ""},
+	{"from_args", "", ""},
+	{"missing", "", ""},
+	{"section:missing", "", ""},
+	{"optional", "", ""},
+	{"same_as_default", 0, ""},
+}
+
+func TestParseSet_Required(t *testing.T) {
+	os.Setenv("XFLAG_TEST_FROM_ENV", "x")
+	defer os.Unsetenv("XFLAG_TEST_FROM_ENV")
+
+	fset := requiredFlags.flagSet()
+	c := New(ini.New(nil), []string{"--from_args", "x", "--same_as_default", "0"})
+	c.Env("xflag_test")
+	c.Require("key1", "from_env", "from_args", "missing", "section:missing", "same_as_default")
+	if err := c.Files("./testdata/file1.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	errs, ok := c.ParseSet(fset).(Errors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf(`A single error expected, got "%v".`, errs)
+	}
+	exp := &RequiredError{Flags: []string{"missing", "section:missing"}}
+	if !reflect.DeepEqual(errs[0], exp) {
+		t.Errorf(`Expected "%v", got "%v".`, exp, errs[0])
+	}
+	if res := exp.Error(); res != `required flags are not set: "missing", "section:missing"` {
+		t.Errorf(`Unexpected error message "%s".`, res)
+	}
+}
+
+func TestContext_Bind_Required(t *testing.T) {
+	var conf struct {
+		Password string `required:"true"`
+	}
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	c := New(ini.New(nil), nil)
+	if err := c.Bind(fset, &conf); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err == nil {
+		t.Errorf("Error expected, got nil.")
+	}
+}
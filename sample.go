@@ -0,0 +1,164 @@
+package xflag
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/goaltools/xflag/cflag/types"
+)
+
+// WriteSample writes an INI configuration file with default values of
+// all the flags of the flag set to the w. Flag names are split into
+// sections and keys using the Separator and ArrLiteral. Usage strings
+// of the flags are written as comments. Every element of slice flags
+// is written on a separate "key[] = value" line, every pair of map
+// flags is written as "key{name} = value". Default values
+// of secret flags are not written.
+// Example of the output:
+//	# name of the user
+//	name = James
+//
+//	[database]
+//	# list of hosts
+//	hosts[] = localhost
+//	hosts[] = 127.0.0.1
+func (c *Context) WriteSample(w io.Writer, fset *flag.FlagSet) error {
+	var buf bytes.Buffer
+	err := c.writeINI(&buf, fset, func(f *flag.Flag, arr bool) []string {
+		switch {
+		case c.marked(f.Name, secret) && arr:
+			return nil
+		case c.marked(f.Name, secret):
+			return []string{""}
+		case arr:
+			return types.Split(f.DefValue)
+		}
+		return []string{f.DefValue}
+	})
+	if err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// writeINI writes the flags of the flag set grouped by sections
+// in INI format to the buffer. Values of the flags are
+// received using the values function.
+func (c *Context) writeINI(buf *bytes.Buffer, fset *flag.FlagSet, values func(*flag.Flag, bool) []string) (err error) {
+	// Group the flags by sections. Flags of the default
+	// section go first.
+	var sects []string
+	flags := map[string][]*flag.Flag{}
+	fset.VisitAll(func(f *flag.Flag) {
+		path, _ := c.flagPath(f.Name)
+		s := section(path)
+		if _, ok := flags[s]; !ok {
+			sects = append(sects, s)
+		}
+		flags[s] = append(flags[s], f)
+	})
+	sort.Strings(sects)
+
+	for i, s := range sects {
+		if s != "" {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			fmt.Fprintf(buf, "[%s]\n", s)
+		}
+		for j, f := range flags[s] {
+			if j > 0 {
+				buf.WriteString("\n")
+			}
+			if err := c.writeINIFlag(buf, f, values); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeINIFlag writes the usage of the flag as a comment
+// followed by its key-value pairs to the buffer.
+func (c *Context) writeINIFlag(buf *bytes.Buffer, f *flag.Flag, values func(*flag.Flag, bool) []string) error {
+	// Keys of the INI format are joined using "."
+	// the same way config/ini does it.
+	path, arr := c.flagPath(f.Name)
+	if len(path) > 1 {
+		path = path[1:]
+	}
+	k := strings.Join(path, ".")
+	if strings.ContainsAny(k, "=#[\"") || strings.IndexFunc(k, unicode.IsSpace) >= 0 {
+		return fmt.Errorf(`flag "%s" cannot be represented as an INI key`, f.Name)
+	}
+	m := c.isMap(f.Name)
+	switch {
+	case m:
+		k += "{%s}"
+	case arr:
+		k += "[]"
+	}
+
+	for _, l := range strings.Split(f.Usage, "\n") {
+		if l != "" {
+			fmt.Fprintf(buf, "# %s\n", l)
+		}
+	}
+	vs := values(f, arr)
+	if len(vs) == 0 && arr {
+		// An empty value would add an empty element,
+		// so keep the key commented out.
+		if m {
+			k = fmt.Sprintf(k, "")
+		}
+		fmt.Fprintf(buf, "# %s =\n", k)
+	}
+	for _, v := range vs {
+		key := k
+		if m {
+			// Pairs of maps are written as "key{name} = value".
+			i := strings.Index(v, "=")
+			if i < 0 || strings.ContainsAny(v[:i], "=#[]{}\"") {
+				return fmt.Errorf(`pair "%s" of flag "%s" cannot be represented in INI`, v, f.Name)
+			}
+			key, v = fmt.Sprintf(k, v[:i]), v[i+1:]
+		}
+		q, err := quoteINI(v)
+		if err != nil {
+			return fmt.Errorf(`value of flag "%s": %v`, f.Name, err)
+		}
+		fmt.Fprintf(buf, "%s = %s\n", key, q)
+	}
+	return nil
+}
+
+// quoteINI returns the value in a form that is parsed by the INI
+// parser back into the original value. Values with leading or
+// trailing spaces, comment characters, or a leading double quote
+// are put into double quotes. The "${" is escaped as "$${", so it is
+// not expanded as a reference. An error is returned if the value
+// cannot be represented, e.g. it is multiline or has to be quoted
+// but contains double quotes.
+func quoteINI(v string) (string, error) {
+	if strings.ContainsAny(v, "\r\n") {
+		return "", fmt.Errorf(`multiline value "%s" is not supported by INI`, v)
+	}
+	v = strings.Replace(v, "${", "$${", -1)
+	if v == "" {
+		return v, nil
+	}
+	first, last := rune(v[0]), rune(v[len(v)-1])
+	if !unicode.IsSpace(first) && !unicode.IsSpace(last) && first != '"' && !strings.Contains(v, "#") {
+		return v, nil
+	}
+	if strings.Contains(v, `"`) {
+		return "", fmt.Errorf(`value "%s" cannot be quoted in INI`, v)
+	}
+	return `"` + v + `"`, nil
+}
@@ -0,0 +1,97 @@
+package xflag
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/goaltools/xflag/cflag/types"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+var sampleFlags = flagDefs{
+	{"name", "James", "name of the user"},
+	{"quoted", " # not a comment ", ""},
+	{"database:hosts[]", []string{"localhost", "127.0.0.1"}, "list of hosts\nsecond line"},
+	{"database:port", 5432, ""},
+	{"empty[]", []int(nil), ""},
+	{"a:b:c", true, ""},
+}
+
+func TestContext_WriteSample(t *testing.T) {
+	var buf bytes.Buffer
+	c := New(ini.New(nil), nil)
+	if err := c.WriteSample(&buf, sampleFlags.flagSet()); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	exp := `# empty[] =
+
+# name of the user
+name = James
+
+quoted = " # not a comment "
+
+[a]
+b.c = true
+
+[database]
+# list of hosts
+# second line
+hosts[] = localhost
+hosts[] = 127.0.0.1
+
+port = 5432
+`
+	if res := buf.String(); res != exp {
+		t.Errorf(`Expected "%s", got "%s".`, exp, res)
+	}
+
+	// Make sure the sample is parsed back into the default values.
+	f, err := ioutil.TempFile("", "xflag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	buf.WriteTo(f)
+	f.Close()
+
+	fset := sampleFlags.flagSet()
+	fset.Lookup("name").Value.Set("")
+	fset.Lookup("quoted").Value.Set("")
+	fset.Lookup("database:hosts[]").Value.Set(types.EOI)
+	c = New(ini.New(nil), nil)
+	if err := c.Files(f.Name()); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	fset.VisitAll(func(f *flag.Flag) {
+		if res := f.Value.String(); res != f.DefValue {
+			t.Errorf(`"%s": Expected "%s", got "%s".`, f.Name, f.DefValue, res)
+		}
+	})
+}
+
+func TestQuoteINI(t *testing.T) {
+	for inp, exp := range map[string]string{
+		"":            "",
+		"abc":         "abc",
+		`say "hi"`:    `say "hi"`,
+		" leading":    `" leading"`,
+		"trailing\t":  "\"trailing\t\"",
+		"a # b":       `"a # b"`,
+		"${HOME}":     "$${HOME}",
+		" ${a:b}":     `" $${a:b}"`,
+		`"quoted"`:    "",
+		"multi\nline": "",
+	} {
+		res, err := quoteINI(inp)
+		if res != exp || (err != nil) != (exp == "" && inp != "") {
+			t.Errorf(`"%s": Expected "%s", got "%s" (%v).`, inp, exp, res, err)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package xflag
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// Source is a source of values of flags, e.g. a remote key-value
+// store or a map in memory. Sources are an alternative to the
+// config.Interface that requires only one method to be implemented.
+type Source interface {
+	// Lookup should return values associated with the path of
+	// a flag. The path is a name of the flag split by the Separator
+	// without array and map literals, prefixed by the section of its
+	// command, e.g. ["database", "port"] for the "database:port" flag.
+	// Every value is assigned to the flag in order, so the last one
+	// wins for scalar flags, slice flags get all of them, and map
+	// flags expect "key=value" pairs.
+	// Name of the source the values were found in (e.g. a path to
+	// a file or "consul") and their location in it (e.g. "line 3" or
+	// a key in a remote store) are returned as the second and third
+	// arguments. They are used in the provenance and errors.
+	// Nil is expected if there are no values.
+	Lookup(path []string) (values []string, name, location string)
+}
+
+// Map is a Source of values stored in memory, e.g. overrides
+// of default values of flags or values received from elsewhere.
+type Map struct {
+	// Name is a name of the source that is used in
+	// the provenance and errors.
+	Name string
+
+	// Values are lists of values of flags by their paths
+	// joined by the Separator, e.g. "database:port".
+	Values map[string][]string
+
+	// Separator is a string that separates elements of paths
+	// in keys of the Values. ":" is used if it is empty.
+	Separator string
+}
+
+// Lookup returns values of the flag with the path.
+func (m *Map) Lookup(path []string) ([]string, string, string) {
+	sep := m.Separator
+	if sep == "" {
+		sep = ":"
+	}
+	k := strings.Join(path, sep)
+	return m.Values[k], m.Name, k
+}
+
+// Sources sets the list of the sources ParseSet receives values of
+// flags from. Values of a subsequent source override values of the
+// previous ones, command line arguments override all of them.
+// Configuration files and environment variables are sources too,
+// use FilesSource and EnvSource to place them in the list, e.g.:
+//	c.Sources(defaults, c.FilesSource(), remote, c.EnvSource())
+// By default, the list is FilesSource followed by EnvSource.
+func (c *Context) Sources(srcs ...Source) {
+	c.sources = srcs
+}
+
+// FilesSource returns a Source of values of the configuration passed
+// to the New constructor and of the files passed to the Files and
+// Dir methods.
+func (c *Context) FilesSource() Source {
+	return filesSource{c}
+}
+
+// EnvSource returns a Source of values of environment variables.
+// It has no values unless the Env method has been called.
+func (c *Context) EnvSource() Source {
+	return envSource{c}
+}
+
+// sourceList returns the sources set by the Sources method
+// or the default ones.
+func (c *Context) sourceList() []Source {
+	if c.sources == nil {
+		return []Source{filesSource{c}, envSource{c}}
+	}
+	return c.sources
+}
+
+// builtin is implemented by the sources of configuration files and
+// environment variables. They assign values to the flags of their
+// Context themselves, so the semantics of files (e.g. merging
+// of maps) are kept and the provenance gets precise origins.
+type builtin interface {
+	Source
+	context() *Context
+	apply(f *flag.Flag, path []string, arr bool) []*SetError
+}
+
+// apply assigns the values of the flag with the path
+// received from the source to the flag.
+func (c *Context) apply(s Source, f *flag.Flag, path []string, arr bool) []*SetError {
+	if b, ok := s.(builtin); ok && b.context() == c {
+		return b.apply(f, path, arr)
+	}
+	ss, name, loc := s.Lookup(path)
+	if ss == nil {
+		return nil
+	}
+	return c.set(f, ss, arr, Origin{Kind: FromSource, Source: name, Location: loc})
+}
+
+// applyFiles assigns a value associated with the path in configuration
+// files to the flag. Only the value of the file that has priority is
+// assigned, values of the previous files that define the path
+// are recorded as overridden ones.
+func (c *Context) applyFiles(f *flag.Flag, path []string, arr bool) []*SetError {
+	if c.isMap(f.Name) {
+		if ss, o, ok := c.lookupMap(path); ok {
+			return c.setResolved(f, ss, arr, o)
+		}
+		return nil
+	}
+	vs, os := c.lookupAll(path)
+	if len(vs) == 0 {
+		return nil
+	}
+	last := len(vs) - 1
+	for i := 0; i < last; i++ {
+		if ss, ok := strs(vs[i], arr); ok {
+			c.recordRaw(f, ss, arr, os[i])
+		}
+	}
+	if ss, ok := strs(vs[last], arr); ok {
+		return c.setResolved(f, ss, arr, os[last])
+	}
+	return nil
+}
+
+// applyEnv assigns a value of the environment variable
+// associated with the path to the flag.
+func (c *Context) applyEnv(f *flag.Flag, path []string, arr bool) []*SetError {
+	if name, ss, ok := c.lookupEnv(path, arr); ok {
+		return c.set(f, ss, arr, Origin{Kind: FromEnv, Env: name})
+	}
+	return nil
+}
+
+// filesSource is a Source of values of configuration files.
+type filesSource struct {
+	c *Context
+}
+
+// Lookup returns a value associated with the path in the file that
+// has priority, a path to the file it is defined in, and its line.
+// If there is no such value, "key=value" pairs of the section with
+// the path merged across the files are returned. References are
+// resolved, values with references that cannot be resolved
+// are returned as is.
+func (s filesSource) Lookup(path []string) ([]string, string, string) {
+	v, o := s.c.lookup(path)
+	ss, ok := v.Strings()
+	if !ok {
+		ss, ok = strs(v, false)
+	}
+	if v.Interface() == nil || !ok {
+		if ss, o, ok = s.c.lookupMap(path); !ok {
+			return nil, "", ""
+		}
+	}
+	rs := make([]string, len(ss))
+	for i := range ss {
+		r, err := s.c.interpolate(ss[i], nil)
+		if err != nil {
+			r = ss[i]
+		}
+		rs[i] = r
+	}
+	if o.Line > 0 {
+		return rs, o.File, fmt.Sprintf("line %d", o.Line)
+	}
+	return rs, o.File, ""
+}
+
+// context returns the Context of the source.
+func (s filesSource) context() *Context {
+	return s.c
+}
+
+// apply assigns values of configuration files to the flag.
+func (s filesSource) apply(f *flag.Flag, path []string, arr bool) []*SetError {
+	return s.c.applyFiles(f, path, arr)
+}
+
+// envSource is a Source of values of environment variables.
+type envSource struct {
+	c *Context
+}
+
+// Lookup returns a value of the environment variable associated
+// with the path and the name of the variable. The value
+// is not split by the EnvDelimiter.
+func (s envSource) Lookup(path []string) ([]string, string, string) {
+	name, ss, ok := s.c.lookupEnv(path, false)
+	if !ok {
+		return nil, "", ""
+	}
+	return ss, "env", name
+}
+
+// context returns the Context of the source.
+func (s envSource) context() *Context {
+	return s.c
+}
+
+// apply assigns a value of the environment variable to the flag.
+func (s envSource) apply(f *flag.Flag, path []string, arr bool) []*SetError {
+	return s.c.applyEnv(f, path, arr)
+}
@@ -0,0 +1,102 @@
+package xflag
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goaltools/xflag/cflag/types"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+// remote is a Source that emulates a remote key-value store.
+type remote map[string]string
+
+func (r remote) Lookup(path []string) ([]string, string, string) {
+	k := "app/" + path[len(path)-1]
+	if v, ok := r[k]; ok {
+		return []string{v}, "remote", k
+	}
+	return nil, "", ""
+}
+
+func TestContext_Sources(t *testing.T) {
+	os.Setenv("XFLAG_TEST_KEY1", "env_value")
+	defer os.Unsetenv("XFLAG_TEST_KEY1")
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	key1 := fset.String("key1", "", "")
+	hosts := &types.Strings{}
+	fset.Var(hosts, "hosts[]", "")
+	sect := fset.String("section:key1", "", "")
+	port := fset.Int("port", 0, "")
+	fset.Duration("timeout", 0, "")
+
+	defaults := &Map{
+		Name: "defaults",
+		Values: map[string][]string{
+			"key1": {"default1"}, "hosts": {"a", "b"}, "port": {"80", "8080"},
+		},
+	}
+	c := New(ini.New(nil), []string{"--section:key1", "arg_value"})
+	c.Env("xflag_test")
+	c.Sources(defaults, c.EnvSource(), c.FilesSource(), remote{"app/port": "9090", "app/timeout": "1"})
+	if err := c.Files("./testdata/sources.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	err := c.ParseSet(fset)
+	exp := `invalid value "1" for flag "timeout" (source "remote", app/timeout): `
+	if errs, ok := err.(Errors); !ok || len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), exp) {
+		t.Errorf(`Expected "%s...", got "%v".`, exp, err)
+	}
+
+	for _, v := range []struct {
+		val, exp interface{}
+	}{
+		{*key1, "file_value"},
+		{hosts.String(), "[a; b]"},
+		{*sect, "arg_value"},
+		{*port, 9090},
+	} {
+		if v.val != v.exp {
+			t.Errorf(`Expected "%v", got "%v".`, v.exp, v.val)
+		}
+	}
+	exp1 := Origin{Kind: FromSource, Value: "[a; b]", Source: "defaults", Location: "hosts"}
+	if p := c.ProvenanceOf("hosts[]"); p == nil || p.Origin != exp1 {
+		t.Errorf(`Expected "%+v", got "%+v".`, exp1, p)
+	}
+}
+
+func TestContext_FilesSource_EnvSource(t *testing.T) {
+	os.Setenv("XFLAG_TEST_KEY1", "a,b")
+	defer os.Unsetenv("XFLAG_TEST_KEY1")
+
+	c := New(ini.New(nil), nil)
+	c.Env("xflag_test")
+	if err := c.Files("./testdata/sources.ini", "./testdata/sources2.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	for _, v := range []struct {
+		src            Source
+		path           []string
+		exp            []string
+		name, location string
+	}{
+		{c.FilesSource(), []string{"section", "key1"}, []string{"file_section"}, "./testdata/sources.ini", "line 4"},
+		{c.FilesSource(), []string{"key1"}, []string{"file_section_2"}, "./testdata/sources2.ini", "line 1"},
+		{c.FilesSource(), []string{"key2"}, []string{"${section:unknown}"}, "./testdata/sources2.ini", "line 2"},
+		{c.FilesSource(), []string{"labels"}, []string{"env=prod", "tier=web"}, "./testdata/sources2.ini", "line 5"},
+		{c.FilesSource(), []string{"doesNotExist"}, nil, "", ""},
+		{c.EnvSource(), []string{"key1"}, []string{"a,b"}, "env", "XFLAG_TEST_KEY1"},
+		{c.EnvSource(), []string{"doesNotExist"}, nil, "", ""},
+	} {
+		ss, name, loc := v.src.Lookup(v.path)
+		if !reflect.DeepEqual(ss, v.exp) || name != v.name || loc != v.location {
+			t.Errorf(`Path %v: expected "%v, %s, %s", got "%v, %s, %s".`, v.path, v.exp, v.name, v.location, ss, name, loc)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package xflag
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/conveyer/config"
+)
+
+// UnknownKeyError is returned by ParseSet in the Strict mode for every
+// key of configuration files that doesn't match any flag.
+type UnknownKeyError struct {
+	// Key is the unknown key in the "section:key" form, as
+	// a flag name without the array literal.
+	Key string
+
+	// File is a path to the configuration file the key was
+	// found in and Line is a number of the line the key is
+	// defined on (zero if unknown).
+	File string
+	Line int
+
+	// Suggestion is a name of the flag that is the closest
+	// to the Key. It is empty if there are no similar flags.
+	Suggestion string
+}
+
+// Error returns the UnknownKeyError in a human readable format.
+func (e *UnknownKeyError) Error() string {
+	res := fmt.Sprintf(`unknown key "%s"`, e.Key)
+	if e.File != "" {
+		res += fmt.Sprintf(` in "%s"`, e.File)
+	}
+	if e.Line > 0 {
+		res += fmt.Sprintf(`, line %d`, e.Line)
+	}
+	if e.Suggestion != "" {
+		res += fmt.Sprintf(` (did you mean "%s"?)`, e.Suggestion)
+	}
+	return res
+}
+
+// unknown returns errors for every key of the configuration files
+// that doesn't match any flag of the flag set. If the flag set belongs
+// to a command started by the Run method, keys of flags of the other
+// commands of the tree are not reported either.
+func (c *Context) unknown(fset *flag.FlagSet) (errs []*UnknownKeyError) {
+	// Collect the names of the flags without the array literals.
+	known, maps := map[string]bool{}, map[string]bool{}
+	add := func(n string, path []string) {
+		name := strings.Join(path, c.Separator)
+		known[name] = true
+		if c.isMap(n) {
+			maps[name] = true
+			maps[strings.Join(path, ".")] = true
+		}
+
+		// INI configuration joins nested keys with dots.
+		if len(path) > 2 {
+			known[path[0]+c.Separator+strings.Join(path[1:], ".")] = true
+		}
+	}
+	var names []string
+	fset.VisitAll(func(f *flag.Flag) {
+		path, _ := c.flagPath(f.Name)
+		add(f.Name, path)
+		names = append(names, strings.Join(path, c.Separator))
+	})
+	c.root.walk(func(cmd *Command) {
+		section := cmd.section(c.Separator)
+		cmd.Flags.VisitAll(func(f *flag.Flag) {
+			path, _ := c.parseFlagName(f.Name)
+			add(f.Name, append(section[:len(section):len(section)], path...))
+		})
+	})
+
+	confs := append([]file{{conf: c.conf}}, c.files...)
+	for i := range confs {
+		for _, path := range keys(confs[i].conf, nil) {
+			// Keys of the default section are represented
+			// by a path with empty first element.
+			if len(path) > 1 && path[0] == "" {
+				path = path[1:]
+			}
+			key := strings.Join(path, c.Separator)
+			if known[key] || c.mapPair(path, maps) {
+				continue
+			}
+			_, line := value(confs[i].conf, path)
+			errs = append(errs, &UnknownKeyError{
+				Key:        key,
+				File:       fileOf(confs[i].conf, path, confs[i].name),
+				Line:       line,
+				Suggestion: suggest(key, names),
+			})
+		}
+	}
+	return
+}
+
+// mapPair returns true if the path is a key of one of the maps,
+// i.e. is located in their sections or is of "name{key}" form.
+func (c *Context) mapPair(path []string, maps map[string]bool) bool {
+	if len(path) > 1 && maps[strings.Join(path[:len(path)-1], c.Separator)] {
+		return true
+	}
+	prefix := strings.Join(path[:len(path)-1], c.Separator)
+	if prefix != "" {
+		prefix += c.Separator
+	}
+	last := path[len(path)-1]
+	for i := range last {
+		if _, ok := c.mapKey(last, last[:i]); ok && maps[prefix+last[:i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// keys returns paths of all the values of the configuration
+// located at the path, nested objects are traversed recursively.
+func keys(conf config.Interface, path []string) (res [][]string) {
+	names := conf.Names(path...)
+	sort.Strings(names)
+	for _, n := range names {
+		p := append(path[:len(path):len(path)], n)
+		v, _ := value(conf, p)
+		switch v.Interface().(type) {
+		case nil, map[string]interface{}:
+			// Sections and nested objects have no values.
+			res = append(res, keys(conf, p)...)
+		default:
+			res = append(res, p)
+		}
+	}
+	return
+}
+
+// suggest returns the name that is the closest to the key
+// or an empty string if there are no similar names.
+func suggest(key string, names []string) (res string) {
+	max := utf8.RuneCountInString(key) / 4
+	if max < 1 {
+		max = 1
+	}
+	for _, n := range names {
+		if d := distance(key, n); d <= max {
+			res, max = n, d-1
+		}
+	}
+	return
+}
+
+// distance returns the number of insertions, deletions, substitutions,
+// and transpositions of adjacent characters that are required
+// to turn a into b (optimal string alignment distance).
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// minInt returns the smallest of the numbers.
+func minInt(n int, ns ...int) int {
+	for _, v := range ns {
+		if v < n {
+			n = v
+		}
+	}
+	return n
+}
@@ -0,0 +1,94 @@
+package xflag
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/goaltools/xflag/cflag/types"
+	"github.com/goaltools/xflag/config/yaml"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+func strictErrors(t *testing.T, err error) (res []UnknownKeyError) {
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf(`Errors expected, got "%v".`, err)
+	}
+	for i := range errs {
+		res = append(res, *errs[i].(*UnknownKeyError))
+	}
+	return
+}
+
+func TestParseSet_Strict(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.String("name", "", "")
+	fset.Var(&types.Strings{}, "tags[]", "")
+	fset.Int("database:port", 0, "")
+	fset.String("database:hosts", "", "")
+
+	c := New(ini.New(nil), nil)
+	if err := c.Files("./testdata/strict.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Errorf(`No error expected in non-strict mode, got "%v".`, err)
+	}
+
+	c.Strict = true
+	exp := []UnknownKeyError{
+		{Key: "nmae", File: "./testdata/strict.ini", Line: 2, Suggestion: "name"},
+		{Key: "database:hots", File: "./testdata/strict.ini", Line: 10, Suggestion: "database:hosts"},
+		{Key: "database:zzz", File: "./testdata/strict.ini", Line: 11},
+		{Key: "databse:port", File: "./testdata/strict.ini", Line: 6, Suggestion: "database:port"},
+	}
+	if res := strictErrors(t, c.ParseSet(fset)); !reflect.DeepEqual(res, exp) {
+		t.Errorf("Expected %v, got %v.", exp, res)
+	}
+}
+
+func TestParseSet_StrictNested(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.String("database:primary:host", "", "")
+	fset.Int("database:primary:port", 0, "")
+
+	c := New(yaml.New(nil), nil)
+	c.Strict = true
+	if err := c.Files("./testdata/strict.yml"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	exp := []UnknownKeyError{
+		{Key: "database:primary:prot", File: "./testdata/strict.yml", Line: 4, Suggestion: "database:primary:port"},
+	}
+	if res := strictErrors(t, c.ParseSet(fset)); !reflect.DeepEqual(res, exp) {
+		t.Errorf("Expected %v, got %v.", exp, res)
+	}
+}
+
+func TestUnknownKeyError_Error(t *testing.T) {
+	e := &UnknownKeyError{Key: "nmae", File: "a.ini", Line: 2, Suggestion: "name"}
+	exp := `unknown key "nmae" in "a.ini", line 2 (did you mean "name"?)`
+	if res := e.Error(); res != exp {
+		t.Errorf(`Expected "%s", got "%s".`, exp, res)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	for _, v := range []struct {
+		a, b string
+		exp  int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"name", "nmae", 1},
+		{"ab", "ba", 1},
+		{"kitten", "sitting", 3},
+		{"ключ", "клюв", 1},
+	} {
+		if res := distance(v.a, v.b); res != v.exp {
+			t.Errorf(`"%s", "%s": Expected %d, got %d.`, v.a, v.b, v.exp, res)
+		}
+	}
+}
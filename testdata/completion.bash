@@ -0,0 +1,30 @@
+# bash completion for my-tool.
+_my_tool_completion() {
+	local cur prev
+	cur="${COMP_WORDS[COMP_CWORD]}"
+	prev="${COMP_WORDS[COMP_CWORD-1]}"
+	if declare -F _get_comp_words_by_ref >/dev/null; then
+		_get_comp_words_by_ref -n := cur prev
+	fi
+	case "$prev" in
+	'-config'|'--config')
+		COMPREPLY=($(compgen -f -- "$cur"))
+		;;
+	'-features[]'|'--features[]')
+		COMPREPLY=($(compgen -W 'a b' -- "$cur"))
+		;;
+	'-log:level'|'--log:level')
+		COMPREPLY=($(compgen -W 'debug info' -- "$cur"))
+		;;
+	'-database:hosts[]'|'--database:hosts[]'|'-name'|'--name')
+		COMPREPLY=()
+		;;
+	*)
+		COMPREPLY=($(compgen -W '-config -database:hosts[] -features[] -log:level -name -verbose' -- "$cur"))
+		;;
+	esac
+	if declare -F __ltrim_colon_completions >/dev/null; then
+		__ltrim_colon_completions "$cur"
+	fi
+}
+complete -F _my_tool_completion 'my-tool'
@@ -0,0 +1,7 @@
+# fish completion for my-tool.
+complete -c 'my-tool' -o 'config' -d 'Path to the configuration file.' -r -F
+complete -c 'my-tool' -o 'database:hosts[]' -d 'List of the user\'s hosts.' -x
+complete -c 'my-tool' -o 'features[]' -x -a 'a b'
+complete -c 'my-tool' -o 'log:level' -d 'Level of [logging].' -x -a 'debug info'
+complete -c 'my-tool' -o 'name' -d 'Name of the user.' -x
+complete -c 'my-tool' -o 'verbose' -d 'Verbose output.'
@@ -0,0 +1,244 @@
+package xflag
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Format is a format of the usage information.
+type Format int
+
+// Formats of the usage information supported by PrintUsage.
+const (
+	Text     Format = iota // Text is a plain text similar to flag.PrintDefaults.
+	Markdown               // Markdown is a list of flags in Markdown.
+	Roff                   // Roff is an OPTIONS section of a man page.
+)
+
+// usageFlag is a description of a flag used by PrintUsage.
+type usageFlag struct {
+	name, typ, usage, def string
+	key, env              string
+	required              bool
+	deprecated            string
+}
+
+// usageSection is a group of flags of the same section.
+type usageSection struct {
+	name  string
+	flags []usageFlag
+}
+
+// Deprecate marks the flag with the specified name as deprecated.
+// The message, e.g. "use --addr instead", is shown by PrintUsage.
+func (c *Context) Deprecate(name, message string) {
+	c.deprecated[name] = message
+}
+
+// PrintUsage writes usage information of the flags of the flag set
+// to the w in the requested format. Flags are grouped by sections.
+// Every flag is described by its type, usage string, default value,
+// the INI key and the environment variable (if the Env method has been
+// called) it can be set from, and required or deprecated marks.
+// Default values of secret flags are not shown.
+func (c *Context) PrintUsage(w io.Writer, fset *flag.FlagSet, format Format) error {
+	var buf bytes.Buffer
+	sects := c.usageSections(fset)
+	switch format {
+	case Markdown:
+		printMarkdown(&buf, sects)
+	case Roff:
+		printRoff(&buf, sects)
+	default:
+		printText(&buf, sects)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// usageSections returns descriptions of the flags of the flag set
+// grouped by sections. Flags of the default section go first.
+func (c *Context) usageSections(fset *flag.FlagSet) (res []usageSection) {
+	m := map[string][]usageFlag{}
+	fset.VisitAll(func(f *flag.Flag) {
+		path, arr := c.flagPath(f.Name)
+		typ, usage := flag.UnquoteUsage(f)
+		if typ == "value" {
+			switch {
+			case c.isMap(f.Name):
+				typ = "map"
+			case arr:
+				typ = "list"
+			}
+		}
+		u := usageFlag{
+			name:       f.Name,
+			typ:        typ,
+			usage:      usage,
+			key:        c.iniKey(path, arr, c.isMap(f.Name)),
+			required:   c.marked(f.Name, required),
+			deprecated: c.deprecated[f.Name],
+		}
+		if !c.marked(f.Name, secret) && f.DefValue != "" && f.DefValue != "[]" {
+			u.def = f.DefValue
+		}
+		if c.env {
+			u.env = c.EnvMapper(c.envPrefix, path)
+		}
+		s := section(path)
+		m[s] = append(m[s], u)
+	})
+
+	names := make([]string, 0, len(m))
+	for s := range m {
+		names = append(names, s)
+	}
+	sort.Strings(names)
+	for _, s := range names {
+		res = append(res, usageSection{name: s, flags: m[s]})
+	}
+	return
+}
+
+// iniKey returns a key of the flag with the path in INI format.
+func (c *Context) iniKey(path []string, arr, m bool) string {
+	k := path[0]
+	if len(path) > 1 {
+		k = strings.Join(path[1:], ".")
+	}
+	switch {
+	case m:
+		k += "{key}"
+	case arr:
+		k += "[]"
+	}
+	if s := section(path); s != "" {
+		return "[" + s + "] " + k
+	}
+	return k
+}
+
+// title returns a title of the section.
+func (s usageSection) title() string {
+	if s.name == "" {
+		return "General"
+	}
+	return "Section [" + s.name + "]"
+}
+
+// notes returns default value, required and deprecated
+// marks of the flag as a list of strings.
+func (u usageFlag) notes(quote func(string) string) (res []string) {
+	if u.required {
+		res = append(res, "required")
+	}
+	if u.deprecated != "" {
+		res = append(res, "deprecated: "+u.deprecated)
+	}
+	if u.def != "" {
+		res = append(res, "default "+quote(u.def))
+	}
+	return
+}
+
+// printText writes the usage in a plain text format.
+func printText(buf *bytes.Buffer, sects []usageSection) {
+	for i, s := range sects {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "%s:\n", s.title())
+		for _, u := range s.flags {
+			fmt.Fprintf(buf, "  -%s", u.name)
+			if u.typ != "" {
+				fmt.Fprintf(buf, " %s", u.typ)
+			}
+			buf.WriteString("\n")
+			if u.usage != "" {
+				fmt.Fprintf(buf, "    \t%s\n", strings.Replace(u.usage, "\n", "\n    \t", -1))
+			}
+			if ns := u.notes(func(s string) string { return fmt.Sprintf("%q", s) }); len(ns) > 0 {
+				fmt.Fprintf(buf, "    \t(%s)\n", strings.Join(ns, ", "))
+			}
+			fmt.Fprintf(buf, "    \tkey: %s", u.key)
+			if u.env != "" {
+				fmt.Fprintf(buf, "; env: %s", u.env)
+			}
+			buf.WriteString("\n")
+		}
+	}
+}
+
+// printMarkdown writes the usage as Markdown lists.
+func printMarkdown(buf *bytes.Buffer, sects []usageSection) {
+	code := func(s string) string {
+		return "`" + s + "`"
+	}
+	for i, s := range sects {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "### %s\n\n", s.title())
+		for _, u := range s.flags {
+			fmt.Fprintf(buf, "- `-%s`", u.name)
+			if u.typ != "" {
+				fmt.Fprintf(buf, " *%s*", u.typ)
+			}
+			if u.usage != "" {
+				fmt.Fprintf(buf, " - %s", strings.Replace(u.usage, "\n", " ", -1))
+			}
+			buf.WriteString("\n")
+			for _, n := range u.notes(code) {
+				fmt.Fprintf(buf, "  - %s\n", strings.ToUpper(n[:1])+n[1:])
+			}
+			fmt.Fprintf(buf, "  - Key: `%s`\n", u.key)
+			if u.env != "" {
+				fmt.Fprintf(buf, "  - Environment: `%s`\n", u.env)
+			}
+		}
+	}
+}
+
+// printRoff writes the usage as an OPTIONS section of a man page.
+func printRoff(buf *bytes.Buffer, sects []usageSection) {
+	buf.WriteString(".SH OPTIONS\n")
+	for _, s := range sects {
+		fmt.Fprintf(buf, ".SS %s\n", roff(s.title()))
+		for _, u := range s.flags {
+			fmt.Fprintf(buf, ".TP\n\\fB\\-%s\\fR", roff(u.name))
+			if u.typ != "" {
+				fmt.Fprintf(buf, " \\fI%s\\fR", roff(u.typ))
+			}
+			buf.WriteString("\n")
+			if u.usage != "" {
+				fmt.Fprintf(buf, "%s\n", roff(u.usage))
+			}
+			if ns := u.notes(func(s string) string { return `"` + s + `"` }); len(ns) > 0 {
+				fmt.Fprintf(buf, ".br\n(%s)\n", roff(strings.Join(ns, ", ")))
+			}
+			fmt.Fprintf(buf, ".br\nKey: %s", roff(u.key))
+			if u.env != "" {
+				fmt.Fprintf(buf, "; environment: %s", roff(u.env))
+			}
+			buf.WriteString("\n")
+		}
+	}
+}
+
+// roff escapes the text for roff: backslashes and dashes are
+// escaped, lines starting with control characters are prefixed.
+func roff(s string) string {
+	s = strings.Replace(s, `\`, `\e`, -1)
+	s = strings.Replace(s, "-", `\-`, -1)
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		if strings.HasPrefix(lines[i], ".") || strings.HasPrefix(lines[i], "'") {
+			lines[i] = `\&` + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
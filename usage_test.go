@@ -0,0 +1,122 @@
+package xflag
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+var usageFlags = flagDefs{
+	{"name", "James", "Name of the `user`."},
+	{"verbose", false, "Verbose output."},
+	{"database:hosts[]", []string{"a", "b"}, "List of hosts."},
+	{"labels{}", map[string]string(nil), ""},
+	{"database:port", 5432, "Port of the database."},
+	{"database:password", "secret", ""},
+}
+
+func TestContext_PrintUsage(t *testing.T) {
+	for _, v := range []struct {
+		format Format
+		exp    string
+	}{
+		{
+			Text,
+			`General:
+  -labels{} map
+    	key: labels{key}; env: APP_LABELS
+  -name user
+    	Name of the user.
+    	(default "James")
+    	key: name; env: APP_NAME
+  -verbose
+    	Verbose output.
+    	(deprecated: use -log:level instead, default "false")
+    	key: verbose; env: APP_VERBOSE
+
+Section [database]:
+  -database:hosts[] list
+    	List of hosts.
+    	(default "[a; b]")
+    	key: [database] hosts[]; env: APP_DATABASE_HOSTS
+  -database:password string
+    	(required)
+    	key: [database] password; env: APP_DATABASE_PASSWORD
+  -database:port int
+    	Port of the database.
+    	(default "5432")
+    	key: [database] port; env: APP_DATABASE_PORT
+`,
+		},
+		{
+			Markdown,
+			"### General\n\n" +
+				"- `-labels{}` *map*\n" +
+				"  - Key: `labels{key}`\n" +
+				"  - Environment: `APP_LABELS`\n" +
+				"- `-name` *user* - Name of the user.\n" +
+				"  - Default `James`\n" +
+				"  - Key: `name`\n" +
+				"  - Environment: `APP_NAME`\n" +
+				"- `-verbose` - Verbose output.\n" +
+				"  - Deprecated: use -log:level instead\n" +
+				"  - Default `false`\n" +
+				"  - Key: `verbose`\n" +
+				"  - Environment: `APP_VERBOSE`\n" +
+				"\n### Section [database]\n\n" +
+				"- `-database:hosts[]` *list* - List of hosts.\n" +
+				"  - Default `[a; b]`\n" +
+				"  - Key: `[database] hosts[]`\n" +
+				"  - Environment: `APP_DATABASE_HOSTS`\n" +
+				"- `-database:password` *string*\n" +
+				"  - Required\n" +
+				"  - Key: `[database] password`\n" +
+				"  - Environment: `APP_DATABASE_PASSWORD`\n" +
+				"- `-database:port` *int* - Port of the database.\n" +
+				"  - Default `5432`\n" +
+				"  - Key: `[database] port`\n" +
+				"  - Environment: `APP_DATABASE_PORT`\n",
+		},
+	} {
+		c := New(ini.New(nil), nil)
+		c.Env("app")
+		c.Require("database:password")
+		c.Secret("database:password")
+		c.Deprecate("verbose", "use -log:level instead")
+
+		var buf bytes.Buffer
+		if err := c.PrintUsage(&buf, usageFlags.flagSet(), v.format); err != nil {
+			t.Fatalf(`No error expected, got "%v".`, err)
+		}
+		if res := buf.String(); res != v.exp {
+			t.Errorf(`Format %d: Expected "%s", got "%s".`, v.format, v.exp, res)
+		}
+	}
+}
+
+func TestContext_PrintUsage_Roff(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.Int("log:max-size", 10, ".Max size\\n.")
+	c := New(ini.New(nil), nil)
+	c.Require("log:max-size")
+
+	var buf bytes.Buffer
+	if err := c.PrintUsage(&buf, fset, Roff); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	exp := `.SH OPTIONS
+.SS Section [log]
+.TP
+\fB\-log:max\-size\fR \fIint\fR
+\&.Max size\en.
+.br
+(required, default "10")
+.br
+Key: [log] max\-size
+`
+	if res := buf.String(); res != exp {
+		t.Errorf(`Expected "%s", got "%s".`, exp, res)
+	}
+}
@@ -0,0 +1,278 @@
+package xflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule is a validation rule of a flag's value. It gets elements of
+// the value (a single element for scalar flags, "key=value" pairs
+// for map flags) and returns an error if they are not valid.
+type Rule func(elems []string) error
+
+// ValidationError is returned by ParseSet for every value
+// of a flag that doesn't satisfy one of its rules.
+type ValidationError struct {
+	// Flag is a name of the flag and Value is its
+	// value in a human readable format.
+	Flag, Value string
+
+	// Origin describes the source the value was received from.
+	Origin Origin
+
+	// Err is an error returned by the rule.
+	Err error
+}
+
+// Error returns the ValidationError in a human readable format.
+// Values of configuration sources are described the same
+// way SetError does.
+func (e *ValidationError) Error() string {
+	var src string
+	switch e.Origin.Kind {
+	case FromDefault:
+		src = "default value"
+	case FromArgs:
+		src = "command line"
+	default:
+		return (&SetError{
+			Flag:     e.Flag,
+			Value:    e.Value,
+			File:     e.Origin.File,
+			Section:  e.Origin.Section,
+			Line:     e.Origin.Line,
+			Env:      e.Origin.Env,
+			Source:   e.Origin.Source,
+			Location: e.Origin.Location,
+			Err:      e.Err,
+		}).Error()
+	}
+	return fmt.Sprintf(`invalid value "%s" for flag "%s" (%s): %v`, e.Value, e.Flag, src, e.Err)
+}
+
+// Validate attaches the rules to the flag with the specified name.
+// The rules are checked by ParseSet after values from all the
+// sources are applied. Every failure is returned as
+// a *ValidationError.
+func (c *Context) Validate(name string, rules ...Rule) {
+	c.rules[name] = append(c.rules[name], rules...)
+}
+
+// validate checks the values of the flags of the flag set
+// against their rules.
+func (c *Context) validate(fset *flag.FlagSet) (errs []*ValidationError) {
+	fset.VisitAll(func(f *flag.Flag) {
+		rules := c.rules[f.Name]
+		if len(rules) == 0 {
+			return
+		}
+		_, arr := c.flagPath(f.Name)
+		elems := []string{f.Value.String()}
+		if arr {
+			elems = elements(f.Value)
+		}
+		var o Origin
+		if p := c.ProvenanceOf(f.Name); p != nil {
+			o = p.Origin
+		}
+		value, secret := f.Value.String(), c.marked(f.Name, secret)
+		for _, rule := range rules {
+			if err := rule(elems); err != nil {
+				if secret {
+					// Rules may quote the elements in their errors.
+					err = redactErr(err, append([]string{value}, elems...)...)
+				}
+				errs = append(errs, &ValidationError{
+					Flag:   f.Name,
+					Value:  redacted(value, secret),
+					Origin: o,
+					Err:    err,
+				})
+			}
+		}
+	})
+	return
+}
+
+// redacted returns Redacted instead of the value if secret is true.
+func redacted(value string, secret bool) string {
+	if secret {
+		return Redacted
+	}
+	return value
+}
+
+// Min returns a rule that checks that every element is
+// not less than the min. Both numbers and durations
+// (e.g. "1s") are supported, other elements are invalid.
+// It panics if the min is neither a number nor a duration.
+func Min(min string) Rule {
+	mustBound(min)
+	return each(func(s string) error {
+		d, err := compare(s, min)
+		if err != nil {
+			return err
+		}
+		if d < 0 {
+			return fmt.Errorf(`"%s" is less than %s`, s, min)
+		}
+		return nil
+	})
+}
+
+// Max returns a rule that checks that every element is
+// not greater than the max. Both numbers and durations
+// (e.g. "1h") are supported, other elements are invalid.
+// It panics if the max is neither a number nor a duration.
+func Max(max string) Rule {
+	mustBound(max)
+	return each(func(s string) error {
+		d, err := compare(s, max)
+		if err != nil {
+			return err
+		}
+		if d > 0 {
+			return fmt.Errorf(`"%s" is greater than %s`, s, max)
+		}
+		return nil
+	})
+}
+
+// OneOf returns a rule that checks that every element
+// is equal to one of the values.
+func OneOf(values ...string) Rule {
+	return each(func(s string) error {
+		for i := range values {
+			if s == values[i] {
+				return nil
+			}
+		}
+		return fmt.Errorf(`"%s" is not one of "%s"`, s, strings.Join(values, `", "`))
+	})
+}
+
+// Match returns a rule that checks that every element
+// matches the regular expression. It panics if the
+// expression cannot be compiled.
+func Match(expr string) Rule {
+	re := regexp.MustCompile(expr)
+	return each(func(s string) error {
+		if !re.MatchString(s) {
+			return fmt.Errorf(`"%s" does not match "%s"`, s, expr)
+		}
+		return nil
+	})
+}
+
+// NonEmpty returns a rule that checks that the value has
+// elements and none of them is an empty string.
+func NonEmpty() Rule {
+	return func(elems []string) error {
+		if len(elems) == 0 {
+			return errors.New("value is empty")
+		}
+		return each(func(s string) error {
+			if s == "" {
+				return errors.New("value is empty")
+			}
+			return nil
+		})(elems)
+	}
+}
+
+// Len returns a rule that checks that the number of elements of
+// a slice or map flag is in the [min, max] range. Negative max
+// means there is no upper bound.
+func Len(min, max int) Rule {
+	return func(elems []string) error {
+		switch l := len(elems); {
+		case l < min:
+			return fmt.Errorf("%d elements are given, expected at least %d", l, min)
+		case max >= 0 && l > max:
+			return fmt.Errorf("%d elements are given, expected at most %d", l, max)
+		}
+		return nil
+	}
+}
+
+// FileExists returns a rule that checks that every
+// element is a path to an existing file or directory.
+func FileExists() Rule {
+	return each(func(s string) error {
+		_, err := os.Stat(s)
+		return err
+	})
+}
+
+// URL returns a rule that checks that every element is
+// an absolute URL with a scheme and a host.
+func URL() Rule {
+	return each(func(s string) error {
+		u, err := url.Parse(s)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf(`"%s" is not an absolute URL`, s)
+		}
+		return nil
+	})
+}
+
+// each returns a rule that applies the check to every element.
+func each(check func(string) error) Rule {
+	return func(elems []string) error {
+		for i := range elems {
+			if err := check(elems[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// mustBound panics if the bound of the Min or Max
+// rule is neither a number nor a duration.
+func mustBound(b string) {
+	if _, err := strconv.ParseFloat(b, 64); err == nil {
+		return
+	}
+	if _, err := time.ParseDuration(b); err == nil {
+		return
+	}
+	panic(fmt.Sprintf(`xflag: bound "%s" is not a number or a duration`, b))
+}
+
+// compare returns -1, 0, or 1 if a is less than, equal to, or greater
+// than b respectively. Both are parsed as numbers or durations,
+// b is expected to be valid. An error is returned if a cannot
+// be parsed or compared with b.
+func compare(a, b string) (int, error) {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA != nil || errB != nil {
+		da, errDA := time.ParseDuration(a)
+		db, errDB := time.ParseDuration(b)
+		switch {
+		case errA != nil && errDA != nil:
+			return 0, fmt.Errorf(`"%s" is not a number or a duration`, a)
+		case errDA != nil || errDB != nil:
+			return 0, fmt.Errorf(`"%s" cannot be compared with %s`, a, b)
+		}
+		x, y = float64(da), float64(db)
+	}
+	switch {
+	case x < y:
+		return -1, nil
+	case x > y:
+		return 1, nil
+	}
+	return 0, nil
+}
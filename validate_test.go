@@ -0,0 +1,130 @@
+package xflag
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goaltools/xflag/cflag/types"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+func TestParseSet_Validate(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.Int("port", 80, "")
+	fset.String("level", "info", "")
+	fset.Duration("timeout", 0, "")
+	fset.Var(&types.Strings{}, "hosts[]", "")
+	fset.String("valid", "abc", "")
+
+	c := New(ini.New(nil), []string{"--timeout", "-5s"})
+	c.Validate("port", Min("1"), Max("65535"))
+	c.Validate("level", OneOf("debug", "info"))
+	c.Validate("timeout", Min("0s"))
+	c.Validate("hosts[]", Len(1, -1))
+	c.Validate("valid", NonEmpty(), Match("^[a-z]+$"))
+	if err := c.Files("./testdata/validate.ini"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	errs, ok := c.ParseSet(fset).(Errors)
+	if !ok {
+		t.Fatalf(`Errors expected, got "%v".`, errs)
+	}
+	var res []string
+	for i := range errs {
+		res = append(res, errs[i].Error())
+	}
+	exp := []string{
+		`invalid value "[]" for flag "hosts[]" (default value): 0 elements are given, expected at least 1`,
+		`invalid value "verbose" for flag "level" ("./testdata/validate.ini", line 2): "verbose" is not one of "debug", "info"`,
+		`invalid value "70000" for flag "port" ("./testdata/validate.ini", line 1): "70000" is greater than 65535`,
+		`invalid value "-5s" for flag "timeout" (command line): "-5s" is less than 0s`,
+	}
+	if !reflect.DeepEqual(res, exp) {
+		t.Errorf("Expected %q, got %q.", exp, res)
+	}
+}
+
+func TestRules(t *testing.T) {
+	for i, v := range []struct {
+		rule  Rule
+		elems []string
+		valid bool
+	}{
+		{Min("1"), []string{"1", "2.5"}, true},
+		{Min("1"), []string{"2", "0"}, false},
+		{Min("1"), []string{"abc"}, false},
+		{Min("1"), []string{"cpu=2"}, false},
+		{Max("1h"), []string{"5"}, false},
+		{Min("0"), []string{"0s"}, true},
+		{Max("1h"), []string{"59m"}, true},
+		{Max("1h"), []string{"61m"}, false},
+		{OneOf("a", "b"), []string{"a", "b"}, true},
+		{OneOf("a", "b"), []string{"a", "c"}, false},
+		{Match(`^\d+$`), []string{"123"}, true},
+		{Match(`^\d+$`), []string{"12a"}, false},
+		{NonEmpty(), []string{"a"}, true},
+		{NonEmpty(), []string{""}, false},
+		{NonEmpty(), nil, false},
+		{Len(1, 2), []string{"a", "b"}, true},
+		{Len(1, 2), []string{"a", "b", "c"}, false},
+		{FileExists(), []string{"./testdata/file1.ini"}, true},
+		{FileExists(), []string{"./testdata/nonexistent.ini"}, false},
+		{URL(), []string{"https://example.com/path"}, true},
+		{URL(), []string{"example.com"}, false},
+		{URL(), []string{"http://%zz"}, false},
+	} {
+		if err := v.rule(v.elems); (err == nil) != v.valid {
+			t.Errorf(`Test %d: %v: expected valid = %v, got "%v".`, i, v.elems, v.valid, err)
+		}
+	}
+}
+
+func TestMinMax_InvalidBound(t *testing.T) {
+	for _, rule := range []func(string) Rule{Min, Max} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Panic expected.")
+				}
+			}()
+			rule("abc")
+		}()
+	}
+}
+
+func TestParseSet_ValidateSource(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.Int("database:port", 80, "")
+
+	c := New(ini.New(nil), nil)
+	c.Sources(&Map{Name: "consul", Values: map[string][]string{"database:port": {"70000"}}})
+	c.Validate("database:port", Max("65535"))
+	exp := `invalid value "70000" for flag "database:port" (source "consul", database:port): "70000" is greater than 65535`
+	if err := c.ParseSet(fset); err == nil || err.Error() != exp {
+		t.Errorf(`Expected "%s", got "%v".`, exp, err)
+	}
+}
+
+func TestParseSet_ValidateSecret(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.String("password", "", "")
+	fset.Var(&types.Strings{}, "tokens[]", "")
+
+	c := New(ini.New(nil), []string{"-password", "hunter2", "-tokens[]", "abc", "-tokens[]", "t0k3n"})
+	c.Secret("password", "tokens[]")
+	c.Validate("password", Match("^[a-z]+$"))
+	c.Validate("tokens[]", OneOf("abc"))
+	errs, ok := c.ParseSet(fset).(Errors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf(`Two errors expected, got "%v".`, errs)
+	}
+	for _, err := range errs {
+		msg := err.Error()
+		if strings.Contains(msg, "hunter2") || strings.Contains(msg, "t0k3n") {
+			t.Errorf(`Secret is not expected in "%s".`, msg)
+		}
+	}
+}
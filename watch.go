@@ -0,0 +1,286 @@
+package xflag
+
+import (
+	"flag"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/goaltools/xflag/cflag/types"
+)
+
+// Watcher polls configuration files of a Context for changes
+// and re-applies their values to the flags.
+// Values of the flags are updated while the Watcher is locked
+// for writing. So, goroutines that read the flags concurrently
+// must hold the read lock:
+//	w.RLock()
+//	port := *portFlag
+//	w.RUnlock()
+type Watcher struct {
+	sync.RWMutex
+
+	c    *Context
+	fset *flag.FlagSet
+
+	mu    sync.Mutex
+	stats map[string]stat
+	subs  []func(changed []string, err error)
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// stat is a state of a file that is used
+// to detect its modifications.
+type stat struct {
+	mod  time.Time
+	size int64
+}
+
+// Watch starts polling the files that were passed to the Files method
+// every interval. Files they include and directories that were passed
+// to the Dir method are polled too. Once some of them are modified,
+// all the files are parsed again (directories are listed again, so new
+// files are picked up) and values of the flags of the flag set that
+// were not set on the command line are updated.
+// Watch is expected to be called after ParseSet. Use the Close method
+// of the returned Watcher to stop polling.
+func (c *Context) Watch(fset *flag.FlagSet, interval time.Duration) *Watcher {
+	w := &Watcher{
+		c:     c,
+		fset:  fset,
+		stats: statFiles(c.files, c.dirs),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go w.poll(interval)
+	return w
+}
+
+// Subscribe registers a function that is called after every reload.
+// It receives names of the flags whose values have changed.
+// If some of the files cannot be parsed, the function is called
+// with an error and values of the flags remain unchanged.
+func (w *Watcher) Subscribe(fn func(changed []string, err error)) {
+	w.mu.Lock()
+	w.subs = append(w.subs, fn)
+	w.mu.Unlock()
+}
+
+// Value returns the current value of the flag with the specified
+// name in a human readable format. It is safe for concurrent use.
+func (w *Watcher) Value(name string) string {
+	w.RLock()
+	defer w.RUnlock()
+	if f := w.fset.Lookup(name); f != nil {
+		return f.Value.String()
+	}
+	return ""
+}
+
+// Reload parses the configuration files again and updates the flags
+// immediately, no matter whether the files were modified.
+// Names of the flags whose values have changed are returned.
+// Subscribers are notified if there are changes or errors.
+func (w *Watcher) Reload() ([]string, error) {
+	changed, err := w.reload()
+	if len(changed) > 0 || err != nil {
+		w.mu.Lock()
+		subs := w.subs
+		w.mu.Unlock()
+		for i := range subs {
+			subs[i](changed, err)
+		}
+	}
+	return changed, err
+}
+
+// Provenance is an equivalent of the Provenance method of the Context
+// that is safe for concurrent use with reloads.
+func (w *Watcher) Provenance() []*Provenance {
+	w.RLock()
+	defer w.RUnlock()
+	return w.c.Provenance()
+}
+
+// ProvenanceOf is an equivalent of the ProvenanceOf method of the Context
+// that is safe for concurrent use with reloads.
+func (w *Watcher) ProvenanceOf(name string) *Provenance {
+	w.RLock()
+	defer w.RUnlock()
+	return w.c.ProvenanceOf(name)
+}
+
+// Close stops polling the files.
+func (w *Watcher) Close() {
+	close(w.stop)
+	<-w.done
+}
+
+// poll checks whether the files were modified every interval
+// and reloads them if necessary until the Watcher is closed.
+func (w *Watcher) poll(interval time.Duration) {
+	defer close(w.done)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-t.C:
+			if w.modified() {
+				w.Reload()
+			}
+		}
+	}
+}
+
+// modified returns true if some of the files were
+// modified since the last check.
+func (w *Watcher) modified() bool {
+	w.RLock()
+	stats := statFiles(w.c.files, w.c.dirs)
+	w.RUnlock()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	res := len(stats) != len(w.stats)
+	for name, s := range stats {
+		if w.stats[name] != s {
+			res = true
+		}
+	}
+	w.stats = stats
+	return res
+}
+
+// reload parses the files and re-applies their values
+// to the flags that were not set on the command line.
+func (w *Watcher) reload() (changed []string, err error) {
+	// Parse the files before locking, so readers are not
+	// blocked and nothing is changed in case of error.
+	w.RLock()
+	files, dirs, err := w.c.reparse()
+	w.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	w.Lock()
+	defer w.Unlock()
+	w.c.files, w.c.dirs = files, dirs
+
+	var errs Errors
+	w.fset.VisitAll(func(f *flag.Flag) {
+		// Command line arguments have the highest priority,
+		// so such flags are not changed.
+		hist := w.c.origins[f.Name]
+		if len(hist) > 0 && hist[len(hist)-1].Kind == FromArgs {
+			return
+		}
+
+		old := f.Value.String()
+		w.c.reset(f)
+		for _, err := range w.c.process(f) {
+			if w.c.Warn != nil {
+				w.c.Warn(err)
+				continue
+			}
+			errs = append(errs, err)
+		}
+		if f.Value.String() != old {
+			changed = append(changed, f.Name)
+		}
+	})
+	if len(errs) > 0 {
+		return changed, errs
+	}
+	return changed, nil
+}
+
+// reparse parses the files of the Context again and lists the
+// directories that were passed to the Dir method, so their new files
+// are parsed and the removed ones are dropped. Fixed files are kept
+// as is. The new files and directories are returned, the Context
+// is not changed.
+func (c *Context) reparse() (files []file, dirs []*dir, err error) {
+	for i, j := 0, 0; ; i++ {
+		// Files of the directories are replaced by their new lists.
+		for ; j < len(c.dirs) && c.dirs[j].at == i; j++ {
+			d := *c.dirs[j]
+			fs, err := d.list()
+			if err != nil {
+				return nil, nil, err
+			}
+			i += d.n
+			d.at, d.n = len(files), len(fs)
+			files, dirs = append(files, fs...), append(dirs, &d)
+		}
+		if i >= len(c.files) {
+			return files, dirs, nil
+		}
+		f := c.files[i]
+		if !f.fixed {
+			conf, err := f.conf.New(f.name)
+			if err != nil {
+				return nil, nil, err
+			}
+			f = file{name: f.name, conf: conf}
+		}
+		files = append(files, f)
+	}
+}
+
+// reset assigns the default value to the flag.
+func (c *Context) reset(f *flag.Flag) {
+	if _, arr := c.parseFlagName(f.Name); !arr {
+		f.Value.Set(f.DefValue)
+		return
+	}
+
+	// Slices are cleaned before their elements are added.
+	switch v := f.Value.(type) {
+	case *slice:
+		types.Reset(v.Value)
+		v.sync()
+	default:
+		types.Reset(v)
+	}
+	for _, s := range types.Split(f.DefValue) {
+		f.Value.Set(s)
+	}
+	f.Value.Set(types.EOI)
+}
+
+// statFiles returns states of the files, the files they include,
+// and the directories. Files that cannot be accessed are ignored.
+func statFiles(files []file, dirs []*dir) map[string]stat {
+	res := map[string]stat{}
+	add := func(name string) {
+		if fi, err := os.Stat(name); err == nil {
+			res[name] = stat{mod: fi.ModTime(), size: fi.Size()}
+		}
+	}
+	for i := range files {
+		if files[i].fixed {
+			continue
+		}
+		add(files[i].name)
+
+		// Included files are reported by the configurations
+		// that implement FileLocator.
+		for _, path := range keys(files[i].conf, nil) {
+			if len(path) > 1 && path[0] == "" {
+				path = path[1:]
+			}
+			if name := fileOf(files[i].conf, path, ""); name != "" {
+				add(name)
+			}
+		}
+	}
+	for i := range dirs {
+		add(dirs[i].path)
+	}
+	return res
+}
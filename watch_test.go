@@ -0,0 +1,180 @@
+package xflag
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/goaltools/xflag/config/ini"
+)
+
+var watchFlags = flagDefs{
+	{"name", "James", ""},
+	{"age", 18, ""},
+	{"tags[]", []string(nil), ""},
+	{"arg", "", ""},
+}
+
+func writeTempFile(t *testing.T, f *os.File, s string) {
+	if err := ioutil.WriteFile(f.Name(), []byte(s), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	f, err := ioutil.TempFile("", "xflag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	fset := watchFlags.flagSet()
+	c := New(ini.New(nil), []string{"--arg", "value"})
+	writeTempFile(t, f, "name = Bob\ntags[] = a\narg = file\n")
+	if err := c.Files(f.Name()); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	w := c.Watch(fset, time.Hour)
+	defer w.Close()
+
+	var notified []string
+	w.Subscribe(func(changed []string, err error) {
+		notified = changed
+	})
+
+	writeTempFile(t, f, "age = 33\narg = changed\n")
+	changed, err := w.Reload()
+	if err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if exp := []string{"age", "name", "tags[]"}; !reflect.DeepEqual(changed, exp) || !reflect.DeepEqual(notified, exp) {
+		t.Errorf(`Expected "%v", got "%v" and "%v".`, exp, changed, notified)
+	}
+	for name, exp := range map[string]string{
+		"name":   "James",
+		"age":    "33",
+		"tags[]": "[]",
+		"arg":    "value",
+	} {
+		if res := w.Value(name); res != exp {
+			t.Errorf(`"%s": Expected "%s", got "%s".`, name, exp, res)
+		}
+	}
+
+	// Values must remain unchanged if a file is invalid.
+	writeTempFile(t, f, "[invalid\n")
+	if _, err := w.Reload(); err == nil {
+		t.Errorf("Error expected, got nil.")
+	}
+	if res := w.Value("age"); res != "33" {
+		t.Errorf(`Expected "33", got "%s".`, res)
+	}
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	f, err := ioutil.TempFile("", "xflag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	fset := watchFlags.flagSet()
+	c := New(ini.New(nil), []string{"--arg", "value"})
+	writeTempFile(t, f, "name = Bob\ntags[] = a\narg = file\n")
+	if err := c.Files(f.Name()); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	w := c.Watch(fset, 10*time.Millisecond)
+	defer w.Close()
+
+	ch := make(chan []string, 1)
+	w.Subscribe(func(changed []string, err error) {
+		ch <- changed
+	})
+	writeTempFile(t, f, "name = Jack Sparrow\n")
+
+	select {
+	case changed := <-ch:
+		if exp := []string{"name", "tags[]"}; !reflect.DeepEqual(changed, exp) {
+			t.Errorf(`Expected "%v", got "%v".`, exp, changed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Modification of the file has not been detected.")
+	}
+	if res := w.Value("name"); res != "Jack Sparrow" {
+		t.Errorf(`Expected "Jack Sparrow", got "%s".`, res)
+	}
+}
+
+func TestWatcher_IncludesAndDirs(t *testing.T) {
+	root, err := ioutil.TempDir("", "xflag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	write := func(name, s string) {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.ini", "@include base.ini\n")
+	write("base.ini", "name = Bob\n")
+	if err := os.Mkdir(filepath.Join(root, "conf.d"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write("conf.d/10-age.ini", "age = 20\n")
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.String("name", "James", "")
+	fset.Int("age", 18, "")
+	c := New(ini.New(nil), nil)
+	if err := c.Files(filepath.Join(root, "main.ini")); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.Dir(filepath.Join(root, "conf.d")); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	w := c.Watch(fset, time.Hour)
+	defer w.Close()
+
+	// Modifications of the included files are detected.
+	write("base.ini", "name = Jack Sparrow\n")
+	if !w.modified() {
+		t.Errorf("Modification of the included file has not been detected.")
+	}
+
+	// New files of the directories are picked up.
+	write("conf.d/20-age.ini", "age = 30\n")
+	if !w.modified() {
+		t.Errorf("New file of the directory has not been detected.")
+	}
+	if _, err := w.Reload(); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	for name, exp := range map[string]string{
+		"name": "Jack Sparrow",
+		"age":  "30",
+	} {
+		if res := w.Value(name); res != exp {
+			t.Errorf(`"%s": Expected "%s", got "%s".`, name, exp, res)
+		}
+	}
+	if p := w.ProvenanceOf("age"); p == nil || p.File != filepath.Join(root, "conf.d", "20-age.ini") {
+		t.Errorf(`The value of "age" is expected to come from the new file, got %+v.`, p)
+	}
+}
@@ -3,14 +3,18 @@
 package xflag
 
 import (
+	"errors"
 	"flag"
 	"os"
 	"strings"
 
 	"github.com/goaltools/xflag/cflag/types"
+	"github.com/goaltools/xflag/config/json"
+	"github.com/goaltools/xflag/config/toml"
+	"github.com/goaltools/xflag/config/yaml"
 
 	"github.com/conveyer/config"
-	"github.com/conveyer/config/ini"
+	"github.com/goaltools/xflag/config/ini"
 )
 
 // Example:
@@ -36,8 +40,9 @@ import (
 // Context represents a single instance of xflag.
 // It contains available arguments and parsed configuration files.
 type Context struct {
-	args []string
-	conf config.Interface
+	args  []string
+	conf  config.Interface
+	files []file
 
 	// Separator is a string that separates different objects or
 	// section from key in flag names.
@@ -53,6 +58,73 @@ type Context struct {
 	// using the New constructor.
 	// Flag name with the array literal may look as "mySection:myKey[]".
 	ArrLiteral string
+
+	// MapLiteral is a string that is if included at the end of a flag
+	// name means that the flag must be treated as a map of "key=value"
+	// pairs (e.g. types.StringMap). Values of such flags are taken from
+	// all keys of the section (object in terms of config.Interface)
+	// with the flag's path, or from the keys of the flag's section
+	// that look like "name{key}".
+	// By default "{}" is used as a map literal if Context is allocated
+	// using the New constructor.
+	// Flag name with the map literal may look as "mySection:myKey{}".
+	MapLiteral string
+
+	// Warn is a function that is called for every value of configuration
+	// that cannot be assigned to its flag. If it is nil (the default),
+	// such values are collected and returned by the ParseSet method
+	// as an error instead.
+	Warn func(err *SetError)
+
+	// EnvMapper is a function that returns a name of the environment
+	// variable a flag with the specified path is looked up in
+	// if the Env method has been called.
+	// By default EnvName is used as a mapper if Context is allocated
+	// using the New constructor.
+	EnvMapper func(prefix string, path []string) string
+
+	// EnvDelimiter is a string that separates elements of slice
+	// flags' values in environment variables.
+	// By default "," is used as a delimiter if Context is allocated
+	// using the New constructor.
+	// Environment variable with the delimiter may look as "APP_NAMES=a,b,c".
+	EnvDelimiter string
+
+	// Strict is a mode that makes ParseSet return an *UnknownKeyError
+	// for every key of configuration files that doesn't match any flag.
+	// It is disabled by default.
+	Strict bool
+
+	// Formats maps extensions of configuration files, e.g. ".ini",
+	// to the configurations the Dir method parses such files with.
+	// By default INI, JSON, YAML, and TOML files are supported
+	// if Context is allocated using the New constructor.
+	Formats map[string]config.Interface
+
+	env       bool
+	envPrefix string
+
+	sources []Source
+	dirs    []*dir
+
+	origins  map[string][]Origin
+	marks    map[string]mark
+	sections map[string][]string
+	rules    map[string][]Rule
+
+	deprecated map[string]string
+
+	// root is the command tree started by the Run method.
+	root *Command
+}
+
+// file represents a single parsed configuration file.
+// Fixed files are not read from disk, e.g. they are received
+// from io.Reader, and thus are not reloaded by Watcher.
+type file struct {
+	name  string
+	conf  config.Interface
+	fixed bool
 }
 
 // New allocates and returns a new Context.
@@ -65,6 +137,25 @@ func New(conf config.Interface, args []string) *Context {
 
 		Separator:  ":",
 		ArrLiteral: "[]",
+		MapLiteral: "{}",
+
+		EnvMapper:    EnvName,
+		EnvDelimiter: ",",
+
+		Formats: map[string]config.Interface{
+			".ini":  ini.New(nil),
+			".json": json.New(nil),
+			".yml":  yaml.New(nil),
+			".yaml": yaml.New(nil),
+			".toml": toml.New(nil),
+		},
+
+		origins:  map[string][]Origin{},
+		marks:    map[string]mark{},
+		sections: map[string][]string{},
+		rules:    map[string][]Rule{},
+
+		deprecated: map[string]string{},
 	}
 }
 
@@ -74,26 +165,70 @@ func New(conf config.Interface, args []string) *Context {
 // Every subsequent file overrides conflicting values of the previous one.
 func (c *Context) Files(files ...string) error {
 	for i := range files {
-		if err := c.conf.Join(files[i]); err != nil {
+		conf, err := c.conf.New(files[i])
+		if err != nil {
 			return err
 		}
+		c.files = append(c.files, file{name: files[i], conf: conf})
 	}
 	return nil
 }
 
 // ParseSet parses flag definitions using the following sources:
 // 1. Configuration files (that may contain Environment variables);
-// 2. Command line arguments list.
-// The latter has higher priority.
+// 2. Environment variables, if the Env method has been called;
+// 3. Command line arguments list.
+// The latter has higher priority. The first two may be reordered
+// and complemented by other sources using the Sources method.
+// Values of configuration files that are rejected by the flags
+// are returned as Errors of *SetError unless the Warn
+// function is specified. In the Strict mode, unknown keys
+// of configuration files are returned as *UnknownKeyError.
+// Required flags that are not set are reported as *RequiredError.
+// Values that don't satisfy validation rules are reported as
+// *ValidationError.
 func (c *Context) ParseSet(fset *flag.FlagSet) error {
 	// Iterate over all available flags.
+	var errs Errors
 	fset.VisitAll(func(f *flag.Flag) {
 		// And try to initialize them using values of configuration files.
-		c.process(f)
+		for _, err := range c.process(f) {
+			if c.Warn != nil {
+				c.Warn(err)
+				continue
+			}
+			errs = append(errs, err)
+		}
 	})
 
+	// Report the keys of configuration files that match no flags.
+	if c.Strict {
+		for _, err := range c.unknown(fset) {
+			errs = append(errs, err)
+		}
+	}
+
 	// Override the flags that are listed in the arguments.
-	return fset.Parse(c.args)
+	if err := c.parseArgs(fset); err != nil {
+		return err
+	}
+	fset.Visit(func(f *flag.Flag) {
+		c.record(f, Origin{Kind: FromArgs})
+	})
+
+	// Make sure the required flags are set.
+	if err := c.missing(fset); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Check the values against the validation rules.
+	for _, err := range c.validate(fset) {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
 // Parse is an equivalent of ParseSet with flag.CommandLine
@@ -113,8 +248,32 @@ func (c *Context) Parse() error {
 //		...
 //	}
 func Parse(files ...string) error {
+	return parse(ini.New(nil), files)
+}
+
+// ParseJSON is an equivalent of Parse that expects
+// JSON configuration files rather than INI.
+func ParseJSON(files ...string) error {
+	return parse(json.New(nil), files)
+}
+
+// ParseYAML is an equivalent of Parse that expects
+// YAML configuration files rather than INI.
+func ParseYAML(files ...string) error {
+	return parse(yaml.New(nil), files)
+}
+
+// ParseTOML is an equivalent of Parse that expects
+// TOML configuration files rather than INI.
+func ParseTOML(files ...string) error {
+	return parse(toml.New(nil), files)
+}
+
+// parse allocates a new Context with the requested configuration,
+// parses the files, and then the default flag set.
+func parse(conf config.Interface, files []string) error {
 	// Allocate a new context using os.Args as input.
-	c := New(ini.New(nil), os.Args[1:])
+	c := New(conf, os.Args[1:])
 
 	// Parse requested configuration files.
 	err := c.Files(files...)
@@ -127,57 +286,198 @@ func Parse(files ...string) error {
 }
 
 // process receives a flag as an input argument and processes it.
-func (c *Context) process(f *flag.Flag) {
+// Errors returned by the flag's Set method are returned as a result.
+func (c *Context) process(f *flag.Flag) (errs []*SetError) {
 	// Split the flag name into parts.
-	path, arr := c.parseFlagName(f.Name)
+	path, arr := c.flagPath(f.Name)
 
-	// Receive a value associated with the path.
-	var v config.ValueInterface
-	switch len(path) > 1 {
-	case true:
-		// If there are many elements in the path, use the first
-		// one as an object path (in terms of config.Interface).
-		v = c.conf.At(path[0]).Value(path[1:]...)
-	default:
-		// Otherwise, use all of them, if any,
-		// as an element path.
-		v = c.conf.Value(path...)
+	// Start the history of the flag's values with its default value.
+	c.origins[f.Name] = []Origin{{Kind: FromDefault, Value: f.DefValue}}
+
+	// Receive values associated with the path from the sources.
+	// Subsequent sources have priority over the previous ones,
+	// by default environment variables have priority
+	// over configuration files.
+	for _, s := range c.sourceList() {
+		errs = append(errs, c.apply(s, f, path, arr)...)
 	}
+	return
+}
 
-	// Process the flag depending on the expected type.
+// strs returns the value as a slice of strings. If arr is false,
+// a single element slice is returned for scalar values.
+// False is returned as a second argument if the value is
+// missing or is of unexpected type.
+func strs(v config.ValueInterface, arr bool) ([]string, bool) {
+	// Process the value depending on the expected type.
 	switch arr {
 	case true:
 		// Make sure a slice can be retrieved from the configuration.
-		ss, ok := v.Strings()
-		if !ok {
-			return
-		}
+		return v.Strings()
+	default:
+		// By default a string value is expected.
+		s, ok := v.String()
+		return []string{s}, ok
+	}
+}
 
-		// Emulate Add behaviour calling Set multiple times.
-		// NOTE: This is supported by xflag/cflag package only
-		// (standard flag package doesn't allow slice flags).
-		for i := range ss {
-			f.Value.Set(ss[i])
+// set assigns the values that were received from the origin
+// to the flag. If all of them are accepted, the origin is added
+// to the history of the flag. Otherwise, errors returned by
+// the Set method are returned.
+func (c *Context) set(f *flag.Flag, ss []string, arr bool, o Origin) (errs []*SetError) {
+	// Emulate Add behaviour calling Set multiple times.
+	// NOTE: This is supported by xflag/cflag package only
+	// (standard flag package doesn't allow slice flags).
+	for i := range ss {
+		v, err := c.readFile(f.Name, ss[i])
+		if err == nil {
+			err = f.Value.Set(v)
 		}
+		if err != nil {
+			value := ss[i]
+			if c.marked(f.Name, secret) {
+				value, err = Redacted, redactErr(err, ss[i], v)
+			}
+			errs = append(errs, &SetError{
+				Flag:     f.Name,
+				Value:    value,
+				File:     o.File,
+				Section:  o.Section,
+				Line:     o.Line,
+				Env:      o.Env,
+				Source:   o.Source,
+				Location: o.Location,
+				Err:      err,
+			})
+		}
+	}
 
-		// Indicate the end of input by using
-		// a special EOI value.
+	// Indicate the end of input by using
+	// a special EOI value.
+	if arr {
 		f.Value.Set(types.EOI)
-	default:
-		// By default a string value is expected, so just set it.
-		if s, ok := v.String(); ok {
-			f.Value.Set(s)
+	}
+	if len(errs) == 0 {
+		c.record(f, o)
+	}
+	return
+}
+
+// redactErr returns the error with the values in its message
+// replaced by Redacted, or the error as is if it doesn't
+// contain them.
+func redactErr(err error, values ...string) error {
+	msg := err.Error()
+	for _, v := range values {
+		if v != "" {
+			msg = strings.Replace(msg, v, Redacted, -1)
 		}
 	}
+	if msg == err.Error() {
+		return err
+	}
+	return errors.New(msg)
+}
+
+// lookup receives a value associated with the path. Files that were
+// passed to the Files method have priority over the configuration the
+// Context was allocated with, subsequent files have priority over
+// the previous ones. Location of the value is returned
+// as a second argument.
+func (c *Context) lookup(path []string) (config.ValueInterface, Origin) {
+	o := Origin{Kind: FromFile, Section: section(path)}
+	for i := len(c.files) - 1; i >= 0; i-- {
+		if v, line := value(c.files[i].conf, path); v.Interface() != nil {
+			o.File, o.Line = fileOf(c.files[i].conf, path, c.files[i].name), line
+			return v, o
+		}
+	}
+	v, line := value(c.conf, path)
+	o.Line = line
+	return v, o
+}
+
+// lookupAll is an equivalent of lookup that returns all the values
+// associated with the path rather than the one that has priority.
+// The values and their locations are ordered from the lowest
+// priority to the highest.
+func (c *Context) lookupAll(path []string) (vs []config.ValueInterface, os []Origin) {
+	add := func(conf config.Interface, name string) {
+		v, line := value(conf, path)
+		if v.Interface() == nil {
+			return
+		}
+		vs = append(vs, v)
+		os = append(os, Origin{
+			Kind:    FromFile,
+			File:    fileOf(conf, path, name),
+			Section: section(path),
+			Line:    line,
+		})
+	}
+	add(c.conf, "")
+	for i := range c.files {
+		add(c.files[i].conf, c.files[i].name)
+	}
+	return
+}
+
+// value receives a value associated with the path from the configuration.
+// A number of the line the value is defined on is returned as a second
+// argument if the configuration implements Locator interface.
+func value(conf config.Interface, path []string) (config.ValueInterface, int) {
+	obj, path := at(conf, path)
+	v := obj.Value(path...)
+	if l, ok := obj.(Locator); ok && v.Interface() != nil {
+		return v, l.Line(path...)
+	}
+	return v, 0
+}
+
+// at returns the object of the configuration the path belongs to
+// and the element path of the value in the object.
+func at(conf config.Interface, path []string) (config.Interface, []string) {
+	// If there are many elements in the path, use the first
+	// one as an object path (in terms of config.Interface).
+	// Otherwise, use all of them, if any, as an element path.
+	if len(path) > 1 {
+		return conf.At(path[0]), path[1:]
+	}
+	return conf, path
+}
+
+// section returns a name of the section (object in terms of
+// config.Interface) the path belongs to.
+func section(path []string) string {
+	if len(path) > 1 {
+		return path[0]
+	}
+	return ""
+}
+
+// flagPath returns a path of the flag with the specified name
+// in the configuration. It is the result of parseFlagName
+// prefixed with the section of the flag's command, if any.
+func (c *Context) flagPath(n string) (path []string, arr bool) {
+	path, arr = c.parseFlagName(n)
+	if s := c.sections[n]; len(s) > 0 {
+		path = append(s[:len(s):len(s)], path...)
+	}
+	return
 }
 
 // parseFlagName splits a flag name into a set of fragments using the
 // earlier specified separator.
 // The second arr argument is true if the flag name ends with an
-// array literal that was expected to be specified earlier as well.
+// array or map literal that was expected to be specified earlier as well.
+// Maps are treated as arrays of "key=value" pairs.
 func (c *Context) parseFlagName(n string) (path []string, arr bool) {
-	// Trim the array literal.
+	// Trim the array or map literal.
 	s := strings.TrimRight(n, c.ArrLiteral)
+	if c.isMap(n) {
+		s = strings.TrimSuffix(n, c.MapLiteral)
+	}
 
 	// Split the name using the specified separator.
 	path = strings.Split(s, c.Separator)
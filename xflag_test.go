@@ -2,12 +2,15 @@ package xflag
 
 import (
 	"flag"
+	"fmt"
 	"go/build"
 	"os"
 	"reflect"
 	"testing"
 
 	"github.com/goaltools/xflag/cflag"
+	"github.com/goaltools/xflag/cflag/types"
+	"github.com/goaltools/xflag/config/yaml"
 )
 
 var (
@@ -20,6 +23,56 @@ var (
 	f6 = cflag.Strings("key2[]", []string{"default"}, "xflag/cflag from default section, file 1")
 )
 
+// flagDefs are definitions of flags the tests parse. Every call of
+// the flagSet method returns new flags, so tests that parse the same
+// flags more than once start with the default values.
+type flagDefs []struct {
+	name  string
+	value interface{}
+	usage string
+}
+
+// flagSet returns a new flag set with the flags. Slices and maps
+// are defined by the types of the cflag/types package.
+func (ds flagDefs) flagSet() *flag.FlagSet {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, d := range ds {
+		switch v := d.value.(type) {
+		case string:
+			fset.String(d.name, v, d.usage)
+		case int:
+			fset.Int(d.name, v, d.usage)
+		case bool:
+			fset.Bool(d.name, v, d.usage)
+		case []string:
+			fset.Var(&types.Strings{Value: append([]string(nil), v...)}, d.name, d.usage)
+		case []int:
+			fset.Var(&types.Ints{Value: append([]int(nil), v...)}, d.name, d.usage)
+		case map[string]string:
+			m := &types.StringMap{}
+			for k := range v {
+				if m.Value == nil {
+					m.Value = map[string]string{}
+				}
+				m.Value[k] = v[k]
+			}
+			fset.Var(m, d.name, d.usage)
+		case map[string]int:
+			m := &types.IntMap{}
+			for k := range v {
+				if m.Value == nil {
+					m.Value = map[string]int{}
+				}
+				m.Value[k] = v[k]
+			}
+			fset.Var(m, d.name, d.usage)
+		default:
+			panic(fmt.Sprintf("unsupported type %T of the flag %s", v, d.name))
+		}
+	}
+	return fset
+}
+
 func TestParse(t *testing.T) {
 	// Simulating "--arg value" input arguments.
 	os.Args = []string{os.Args[0], "--arg", "value"}
@@ -45,6 +98,56 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseJSON(t *testing.T) {
+	os.Args = []string{os.Args[0], "--arg", "json_value"}
+
+	err := ParseJSON("./testdata/file1.json")
+	if err != nil {
+		t.Errorf(`No error expected, got "%v".`, err)
+	}
+
+	for _, v := range []struct {
+		val, exp interface{}
+	}{
+		{*f1, "json_value1"},
+		{*f2, "json_value2"},
+		{*f3, "json_value"},
+		{*f6, []string{"json_value2", "3"}},
+	} {
+		if !reflect.DeepEqual(v.val, v.exp) {
+			t.Errorf(`Incorrect value of the flag. Expected "%s", got "%s".`, v.exp, v.val)
+		}
+	}
+}
+
+func TestParseSet_YAML(t *testing.T) {
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	host := fset.String("db:primary:host", "", "")
+	port := fset.Int("db:primary:port", 0, "")
+	replicas := &types.Strings{}
+	fset.Var(replicas, "db:replicas[]", "")
+
+	c := New(yaml.New(nil), []string{"--db:primary:port", "5433"})
+	if err := c.Files("./testdata/file1.yml", "./testdata/file2.yml"); err != nil {
+		t.Fatalf(`No error expected, got "%v".`, err)
+	}
+	if err := c.ParseSet(fset); err != nil {
+		t.Errorf(`No error expected, got "%v".`, err)
+	}
+
+	for _, v := range []struct {
+		val, exp interface{}
+	}{
+		{*host, "db.example.com"},
+		{*port, 5433},
+		{replicas.Value, []string{"r1.example.com", "r2.example.com"}},
+	} {
+		if !reflect.DeepEqual(v.val, v.exp) {
+			t.Errorf(`Incorrect value of the flag. Expected "%v", got "%v".`, v.exp, v.val)
+		}
+	}
+}
+
 func TestParse_IncorrectFile(t *testing.T) {
 	err := Parse("file_does_not_exist")
 	if err == nil {